@@ -30,7 +30,7 @@ func init() {
 var Latest = cmd.Sub{
 	Name:  "latest",
 	Alias: "l",
-	Short: "Get the latest stable release",
+	Short: "Get the latest release in the selected channel",
 	Args:  &LatestArgs{},
 	Run:   LatestRun,
 }
@@ -43,6 +43,8 @@ type LatestArgs struct {
 // LatestRun carries out finding the latest release
 func LatestRun(r *cmd.Root, c *cmd.Sub) {
 	args := c.Args.(*LatestArgs)
+	SetChannel(r)
+	constraint := GetConstraint(r)
 	found := false
 	for _, p := range providers.All() {
 		log.Infof("\033[1m%s\033[22m checking for match:\n", p)
@@ -51,7 +53,7 @@ func LatestRun(r *cmd.Root, c *cmd.Sub) {
 			log.Warnf("\033[1m%s\033[22m does not match.\n", p)
 			continue
 		}
-		r, err := p.Latest(match)
+		r, err := FindLatest(p, match, constraint)
 		if err != nil {
 			log.Warnf("Could not get latest \033[1m%s\033[22m, reason: %s\n", match[0], err)
 			continue
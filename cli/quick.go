@@ -44,6 +44,8 @@ type QuickArgs struct {
 // QuickRun carries out finding the latest release
 func QuickRun(r *cmd.Root, c *cmd.Sub) {
 	args := c.Args.(*QuickArgs)
+	SetChannel(r)
+	constraint := GetConstraint(r)
 	found := false
 	log.SetFormat(format.Un)
 	for _, p := range providers.All() {
@@ -51,7 +53,7 @@ func QuickRun(r *cmd.Root, c *cmd.Sub) {
 		if len(match) == 0 {
 			continue
 		}
-		r, err := p.Latest(match)
+		r, err := FindLatest(p, match, constraint)
 		if err != nil {
 			continue
 		}
@@ -19,6 +19,7 @@ package cli
 import (
 	"github.com/DataDrake/cli-ng/v2/cmd"
 	"github.com/DataDrake/cuppa/providers"
+	"github.com/DataDrake/cuppa/results"
 	log "github.com/DataDrake/waterlog"
 )
 
@@ -30,7 +31,7 @@ func init() {
 var Releases = cmd.Sub{
 	Name:  "releases",
 	Alias: "r",
-	Short: "Get all stable releases",
+	Short: "Get all releases in the selected channel",
 	Args:  &ReleasesArgs{},
 	Run:   ReleasesRun,
 }
@@ -43,6 +44,8 @@ type ReleasesArgs struct {
 // ReleasesRun carries out finding all releases
 func ReleasesRun(r *cmd.Root, c *cmd.Sub) {
 	args := c.Args.(*ReleasesArgs)
+	SetChannel(r)
+	constraint := GetConstraint(r)
 	found := false
 	for _, p := range providers.All() {
 		log.Infof("\033[1m%s\033[22m checking for match:\n", p)
@@ -52,6 +55,12 @@ func ReleasesRun(r *cmd.Root, c *cmd.Sub) {
 			continue
 		}
 		rs, err := p.Releases(match)
+		if err == nil {
+			rs.Filter(constraint)
+			if rs.Len() == 0 {
+				err = results.NotFound
+			}
+		}
 		if err != nil {
 			log.Warnf("Could not get latest \033[1m%s\033[22m, reason: %s\n", match[0], err)
 			continue
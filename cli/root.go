@@ -18,6 +18,9 @@ package cli
 
 import (
 	"github.com/DataDrake/cli-ng/v2/cmd"
+	"github.com/DataDrake/cuppa/providers"
+	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
 	log "github.com/DataDrake/waterlog"
 	"github.com/DataDrake/waterlog/format"
 	"github.com/DataDrake/waterlog/level"
@@ -28,6 +31,29 @@ import (
 var Root = &cmd.Root{
 	Name:  "cuppa",
 	Short: "Comprehensive Upstream Provider Polling Assistant",
+	Flags: &GlobalFlags{},
+}
+
+// GlobalFlags contains the flags for all subcommands
+type GlobalFlags struct {
+	IncludePrerelease bool   `short:"p" long:"include-prerelease" desc:"Include alpha, beta and rc releases"`
+	Channel           string `short:"c" long:"channel" desc:"Least stable release to include: stable, rc, beta, alpha, dev or snapshot"`
+	Constraint        string `short:"C" long:"constraint" desc:"Only include versions matching a constraint, like \">= 1.2, < 2\" or \"3.11.*\""`
+}
+
+// SetChannel sets the least stable kind of release to include in results
+func SetChannel(r *cmd.Root) {
+	flags := r.Flags.(*GlobalFlags)
+	if flags.IncludePrerelease {
+		results.Channel = version.Alpha
+	}
+	if len(flags.Channel) > 0 {
+		channel, err := version.ParseStability(flags.Channel)
+		if err != nil {
+			log.Fatalf("Invalid channel: %s\n", err)
+		}
+		results.Channel = channel
+	}
 }
 
 func init() {
@@ -38,3 +64,29 @@ func init() {
 	log.SetLevel(level.Info)
 	log.SetFormat(format.Min)
 }
+
+// GetConstraint gets the version constraint for results, which is empty unless one was set
+func GetConstraint(r *cmd.Root) version.Constraint {
+	c, err := version.ParseConstraint(r.Flags.(*GlobalFlags).Constraint)
+	if err != nil {
+		log.Fatalf("Invalid constraint: %s\n", err)
+	}
+	return c
+}
+
+// FindLatest finds the newest release from a provider, which satisfies a constraint
+func FindLatest(p providers.Provider, params []string, c version.Constraint) (r *results.Result, err error) {
+	if c.IsEmpty() {
+		return p.Latest(params)
+	}
+	// The latest release of a provider might not satisfy the constraint, so check all of them
+	rs, err := p.Releases(params)
+	if err != nil {
+		return
+	}
+	rs.Filter(c)
+	if r = rs.Last(); r == nil {
+		err = results.NotFound
+	}
+	return
+}
@@ -27,8 +27,42 @@ import (
 // Config is the configuration for cuppa
 type Config struct {
 	Github struct {
-		Key string `toml:"key"`
+		Key     string            `toml:"key"`
+		Keys    []string          `toml:"keys"`
+		Helper  string            `toml:"helper"`
+		MaxTags int               `toml:"max_tags"`
+		Assets  map[string]string `toml:"assets"`
+		Hosts   []GithubHost      `toml:"hosts"`
 	} `toml:"github"`
+	GitLab struct {
+		Hosts  []string          `toml:"hosts"`
+		Tokens map[string]string `toml:"tokens"`
+	} `toml:"gitlab"`
+	GNOME struct {
+		Policies map[string]string `toml:"policies"`
+	} `toml:"gnome"`
+	GNU struct {
+		Mirror   string `toml:"mirror"`
+		Savannah string `toml:"savannah"`
+	} `toml:"gnu"`
+	PyIndex struct {
+		Indexes []string `toml:"indexes"`
+	} `toml:"pyindex"`
+	Normalize map[string][]string `toml:"normalize"`
+	Schemes   map[string]string   `toml:"schemes"`
+	Tags      struct {
+		Prefixes map[string]string `toml:"prefixes"`
+		Patterns map[string]string `toml:"patterns"`
+	} `toml:"tags"`
+}
+
+// GithubHost is the configuration for a GitHub Enterprise Server instance
+type GithubHost struct {
+	Host    string   `toml:"host"`
+	GraphQL string   `toml:"graphql"`
+	REST    string   `toml:"rest"`
+	Keys    []string `toml:"keys"`
+	Helper  string   `toml:"helper"`
 }
 
 // Global is the config for all of cuppa at runtime
@@ -20,12 +20,19 @@ import (
 	"fmt"
 	"github.com/DataDrake/cuppa/results"
 	"github.com/DataDrake/cuppa/util"
+	"net/url"
 	"regexp"
 	"strings"
 )
 
-// APIDownloadURL is the format string for the metacpan download_url API
-const APIDownloadURL = "https://fastapi.metacpan.org/v1/download_url/%s"
+const (
+	// APIDownloadURL is the format string for the metacpan download_url API
+	APIDownloadURL = "https://fastapi.metacpan.org/v1/download_url/%s"
+	// APIReleaseSearch is the format string for the metacpan release search API
+	APIReleaseSearch = "https://fastapi.metacpan.org/v1/release/_search?q=%s&fields=version,status,maturity,date,download_url&sort=date:desc&size=%d"
+	// MaxReleases is the largest number of releases to request from the release search
+	MaxReleases = 500
+)
 
 // SearchRegex is the regexp for "search.cpan.org"
 var SearchRegex = regexp.MustCompile("https?://*(?:/.*cpan.org)(?:/CPAN)?/authors/id/(.+)$")
@@ -60,26 +67,30 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 	if err != nil {
 		return
 	}
-	url := fmt.Sprintf(APIDownloadURL, module)
 	var rel Release
-	if err = util.FetchJSON(url, "latest", &rel); err != nil {
+	if err = util.FetchJSON(fmt.Sprintf(APIDownloadURL, module), "latest", &rel); err != nil {
 		return
 	}
 	if len(rel.Error) > 0 {
 		err = results.NotFound
 		return
 	}
-	if r = rel.Convert(name); r == nil {
-		err = results.NotFound
+	if r = rel.Convert(name); r == nil || !r.Accepted() {
+		r, err = nil, results.NotFound
 	}
 	return
 }
 
 // Releases finds all matching releases for a CPAN package
 func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
-	if r, err := c.Latest(params); err == nil {
-		rs = results.NewResultSet(params[0])
-		rs.AddResult(r)
+	name := params[0]
+	query := url.QueryEscape(fmt.Sprintf("distribution:\"%s\"", name))
+	var crs Releases
+	if err = util.FetchJSON(fmt.Sprintf(APIReleaseSearch, query, MaxReleases), "releases", &crs); err != nil {
+		return
+	}
+	if rs = crs.Convert(name); rs.Len() == 0 {
+		err = results.NotFound
 	}
 	return
 }
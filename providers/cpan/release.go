@@ -18,6 +18,8 @@ package cpan
 
 import (
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
+	"strings"
 	"time"
 )
 
@@ -26,13 +28,19 @@ type Release struct {
 	Version  string `json:"version"`
 	Status   string `json:"status"`
 	Date     string `json:"date"`
+	Maturity string `json:"maturity"`
 	Location string `json:"download_url"`
 	Error    string `json:"error"`
 }
 
+// IsDeveloper checks if this is a developer (unstable) release
+func (cr *Release) IsDeveloper() bool {
+	return cr.Maturity == "developer" || strings.Contains(cr.Version, "_")
+}
+
 // Convert turns a CPAN release into a Cuppa result
 func (cr *Release) Convert(name string) *results.Result {
-	if cr.Status != "latest" {
+	if cr.Status == "backpan" {
 		return nil
 	}
 	t, _ := time.Parse(time.RFC3339, cr.Date)
@@ -40,5 +48,8 @@ func (cr *Release) Convert(name string) *results.Result {
 	if r.Version[0] == "N/A" {
 		return nil
 	}
+	if cr.IsDeveloper() {
+		r.MarkUnstable(version.Dev)
+	}
 	return r
 }
@@ -20,16 +20,20 @@ import (
 	"github.com/DataDrake/cuppa/results"
 )
 
-// Releases is a collection of CPAN releases
+// Releases is a collection of CPAN releases from the metacpan release search
 type Releases struct {
-	Releases []Release `json:"releases"`
+	Hits struct {
+		Hits []struct {
+			Release Release `json:"fields"`
+		} `json:"hits"`
+	} `json:"hits"`
 }
 
 // Convert turns Releases into a Cuppa ResultSet
 func (crs *Releases) Convert(name string) *results.ResultSet {
 	rs := results.NewResultSet(name)
-	for _, rel := range crs.Releases {
-		if r := rel.Convert(name); r != nil {
+	for _, hit := range crs.Hits.Hits {
+		if r := hit.Release.Convert(name); r != nil {
 			rs.AddResult(r)
 		}
 	}
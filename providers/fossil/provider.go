@@ -0,0 +1,142 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package fossil
+
+import (
+	"fmt"
+	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	"github.com/DataDrake/cuppa/version"
+	log "github.com/DataDrake/waterlog"
+	"html"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// SourceFormat is the format string for Fossil tarballs and zips
+	SourceFormat = "%s/%s/%s/%s.%s"
+	// TagsAPI is the format string for the list of tags from the Fossil JSON interface
+	TagsAPI = "%s/json/tag/list"
+	// TagsPage is the format string for the list of tags from the Fossil web interface
+	TagsPage = "%s/taglist"
+)
+
+var (
+	// TarballRegex matches Fossil tarball and zip downloads, capturing the repo, kind, name and extension
+	TarballRegex = regexp.MustCompile("^(https?://.+?)/(tarball|zip)/[^/]+/([^/]+)\\.(tar\\.gz|zip)$")
+	// TagLinkRegex matches the link to the timeline of a tag in the Fossil web interface
+	TagLinkRegex = regexp.MustCompile("timeline\\?t=([^\"&]+)")
+)
+
+// Tags is the JSON representation of the list of tags from the Fossil JSON interface
+type Tags struct {
+	Payload struct {
+		Tags []string `json:"tags"`
+	} `json:"payload"`
+}
+
+// Provider provides a common interface for each of the backend providers
+type Provider struct{}
+
+// String returns the name of this provider
+func (p Provider) String() string {
+	return "Fossil"
+}
+
+// Match checks to see if this provider can handle this kind of query
+func (p Provider) Match(query string) (params []string) {
+	if strings.HasPrefix(query, "fossil|") {
+		params = append(params, strings.TrimPrefix(query, "fossil|"))
+		return
+	}
+	if sm := TarballRegex.FindStringSubmatch(query); len(sm) > 4 {
+		params = sm[1:]
+	}
+	return
+}
+
+// Latest finds the newest release for a Fossil package
+func (p Provider) Latest(params []string) (r *results.Result, err error) {
+	rs, err := p.Releases(params)
+	if err == nil {
+		r = rs.Last()
+	}
+	return
+}
+
+// Releases finds all matching releases for a Fossil package, named after the file from the query
+// if there is one, or else after the repo itself
+func (p Provider) Releases(params []string) (rs *results.ResultSet, err error) {
+	repo := strings.TrimSuffix(params[0], "/")
+	kind, name, ext := "tarball", path.Base(repo), "tar.gz"
+	if len(params) > 3 {
+		kind, name, ext = params[1], params[2], params[3]
+	}
+	tags, err := listTags(repo)
+	if err != nil {
+		return
+	}
+	// Convert tags to releases
+	rs = results.NewResultSet(repo)
+	rs.SetNormalizer(version.TagRules)
+	for _, tag := range tags {
+		if tag == "trunk" {
+			continue
+		}
+		location := fmt.Sprintf(SourceFormat, repo, kind, url.PathEscape(tag), name, ext)
+		rs.AddResult(results.NewResult(name, tag, location, time.Time{}))
+	}
+	if rs.Len() == 0 {
+		err = results.NotFound
+	}
+	return
+}
+
+// listTags gets the tags of a repo from the JSON interface, falling back to the web interface for
+// servers built without JSON support
+func listTags(repo string) (tags []string, err error) {
+	var list Tags
+	if err = util.FetchJSON(fmt.Sprintf(TagsAPI, repo), "tags", &list); err == nil && len(list.Payload.Tags) > 0 {
+		tags = list.Payload.Tags
+		return
+	}
+	log.Debugf("Falling back to the tag list page for: %s\n", repo)
+	body, err := util.Fetch(fmt.Sprintf(TagsPage, repo), "tags")
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	page, err := ioutil.ReadAll(body)
+	if err != nil {
+		log.Debugf("Failed to read tags: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	seen := make(map[string]bool)
+	for _, sm := range TagLinkRegex.FindAllStringSubmatch(string(page), -1) {
+		if tag, e := url.QueryUnescape(html.UnescapeString(sm[1])); e == nil && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return
+}
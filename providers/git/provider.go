@@ -20,13 +20,25 @@ import (
 	"bufio"
 	"bytes"
 	"github.com/DataDrake/cuppa/results"
-	"io"
+	"github.com/DataDrake/cuppa/util"
+	"github.com/DataDrake/cuppa/version"
+	log "github.com/DataDrake/waterlog"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"strings"
 	"time"
 )
 
+const (
+	// DateCandidates is the number of newest tags to look up commit dates for
+	DateCandidates = 10
+	// TagPrefix is the prefix of every tag reference
+	TagPrefix = "refs/tags/"
+	// PeeledSuffix marks the commit an annotated tag points to
+	PeeledSuffix = "^{}"
+)
+
 // Provider provides a common interface for each of the backend providers
 type Provider struct{}
 
@@ -45,55 +57,148 @@ func (p Provider) Match(query string) (params []string) {
 
 // Latest finds the newest release for a Git package
 func (p Provider) Latest(params []string) (r *results.Result, err error) {
-	rs, err := p.Releases(params)
-	if err == nil {
-		r = rs.Last()
+	rs, refs, err := p.tags(params[0])
+	if err != nil {
+		return
+	}
+	candidates := rs.Newest(DateCandidates)
+	dated(params[0], candidates, refs)
+	rs = results.NewResultSet(params[0])
+	rs.SetNormalizer(version.TagRules)
+	for _, r := range candidates {
+		rs.AddResult(r)
 	}
+	r = rs.Last()
 	return
 }
 
-// Releases finds all matching releases for a Git package
+// Releases finds all matching releases for a Git package, leaving them undated since fetching every tag
+// of a large repo is too expensive
 func (p Provider) Releases(params []string) (rs *results.ResultSet, err error) {
-	name := params[0]
-	pieces := strings.Split(name, "/")
+	rs, _, err = p.tags(params[0])
+	return
+}
+
+// tags lists every tag of a remote repository without dates, along with the tag name of each result
+func (p Provider) tags(name string) (rs *results.ResultSet, refs map[*results.Result]string, err error) {
+	pieces := strings.Split(strings.TrimSuffix(strings.TrimSuffix(name, "/"), "/.git"), "/")
 	repoName := strings.TrimSuffix(pieces[len(pieces)-1], ".git")
-	tmp := "/tmp/" + repoName
-	defer os.RemoveAll(tmp)
-	// Shallow clone repo to temp directory
-	cmd := exec.Command("git", "clone", "--depth=1", name)
-	cmd.Dir = "/tmp"
-	if err := cmd.Run(); err == nil {
-		// Fetch tags from remote
-		cmd = exec.Command("git", "fetch", "--tags", "--depth=1")
-		cmd.Dir = tmp
-		err = cmd.Run()
+	filter := util.NewTagFilter(repoName, name)
+	args := []string{"ls-remote", "--tags", name}
+	if len(filter.Prefix) > 0 {
+		args = append(args, TagPrefix+filter.Prefix+"*")
 	}
-	if err != nil {
+	var buff bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &buff
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if err = cmd.Run(); err != nil {
+		log.Debugf("Failed to list tags: %s\n", err)
 		err = results.Unavailable
 		return
 	}
-	// Read git tags
-	var buff bytes.Buffer
-	read := bufio.NewReader(&buff)
-	cmd = exec.Command("git", "log", "--tags", "-n 10", "--format='%S %cI'")
-	cmd.Dir = tmp
-	cmd.Stdout = &buff
-	cmd.Run()
-	// Convert tags to releases
+	// Convert tags to releases, keeping only the tags of the configured component
 	rs = results.NewResultSet(name)
-	line, _, err := read.ReadLine()
-	for err == nil {
-		pieces = strings.Fields(string(line))
-		tag := pieces[0]
-		date, _ := time.Parse("2006-01-02T15:04:05-07:00", pieces[1])
-		r := results.NewResult(repoName, tag, "git|"+name, date)
+	rs.SetNormalizer(version.TagRules)
+	refs = make(map[*results.Result]string)
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(&buff)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], TagPrefix) {
+			continue
+		}
+		tag := strings.TrimSuffix(strings.TrimPrefix(fields[1], TagPrefix), PeeledSuffix)
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		version, ok := filter.Version(tag)
+		if !ok {
+			continue
+		}
+		r := results.NewResult(repoName, version, "git|"+name, time.Time{})
+		refs[r] = tag
 		rs.AddResult(r)
-		line, _, err = read.ReadLine()
 	}
-	if err != io.EOF || rs.Len() == 0 {
+	if rs.Len() == 0 {
 		err = results.NotFound
+	}
+	return
+}
+
+// dated fills in the dates of the provided tags by fetching only their commits into a private repo
+//
+// Results are ordered by date only when both have one, so dates are dropped again unless every
+// candidate could be dated, to keep the ordering consistent.
+func dated(name string, candidates []*results.Result, refs map[*results.Result]string) {
+	if len(candidates) == 0 {
+		return
+	}
+	tmp, err := ioutil.TempDir("", "cuppa-git-")
+	if err != nil {
+		log.Debugf("Failed to create temp dir: %s\n", err)
+		return
+	}
+	defer os.RemoveAll(tmp)
+	tags := make([]string, len(candidates))
+	for i, r := range candidates {
+		tags[i] = refs[r]
+	}
+	dates, err := fetchDates(tmp, name, tags)
+	if err != nil {
+		return
+	}
+	for _, r := range candidates {
+		if dates[refs[r]].IsZero() {
+			return
+		}
+	}
+	for _, r := range candidates {
+		r.Published = dates[refs[r]]
+	}
+}
+
+// fetchDates reads the dates of the provided tags from a new bare repo in "dir", fetching only their
+// commit and tag objects without any trees or blobs
+func fetchDates(dir, name string, tags []string) (dates map[string]time.Time, err error) {
+	if err = run(dir, nil, "init", "--bare", "--quiet"); err != nil {
+		log.Debugf("Failed to init repo: %s\n", err)
+		return
+	}
+	args := []string{"fetch", "--quiet", "--depth=1", "--filter=tree:0", "--no-tags", name}
+	for _, tag := range tags {
+		ref := TagPrefix + tag
+		args = append(args, "+"+ref+":"+ref)
+	}
+	if err = run(dir, nil, args...); err != nil {
+		log.Debugf("Failed to fetch tags: %s\n", err)
 		return
 	}
-	err = nil
+	var buff bytes.Buffer
+	if err = run(dir, &buff, "for-each-ref", "--format=%(refname:strip=2) %(creatordate:iso-strict)", TagPrefix); err != nil {
+		log.Debugf("Failed to read tags: %s\n", err)
+		return
+	}
+	dates = make(map[string]time.Time)
+	scanner := bufio.NewScanner(&buff)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		dates[fields[0]], _ = time.Parse(time.RFC3339, fields[1])
+	}
 	return
 }
+
+// run executes a git command in a directory
+func run(dir string, out *bytes.Buffer, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out != nil {
+		cmd.Stdout = out
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,68 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFetchDatesCandidatesOnly(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "cuppa-git-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	src, dst := filepath.Join(tmp, "src"), filepath.Join(tmp, "dst")
+	for _, dir := range []string{src, dst} {
+		if err = os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	user := []string{"-c", "user.name=cuppa", "-c", "user.email=cuppa@example.com"}
+	if err = run(src, nil, "init", "--quiet"); err != nil {
+		t.Fatal(err)
+	}
+	if err = run(src, nil, "config", "uploadpack.allowFilter", "true"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{"v1.0", "v1.1", "v1.2"} {
+		if err = run(src, nil, append(user, "commit", "--quiet", "--allow-empty", "-m", tag)...); err != nil {
+			t.Fatal(err)
+		}
+		if err = run(src, nil, append(user, "tag", "-a", "-m", tag, tag)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dates, err := fetchDates(dst, src, []string{"v1.1", "v1.2"})
+	if err != nil {
+		t.Fatalf("Failed to fetch dates: %s", err)
+	}
+	if len(dates) != 2 {
+		t.Errorf("Expected 2 fetched tags, found '%d'", len(dates))
+	}
+	for _, tag := range []string{"v1.1", "v1.2"} {
+		if dates[tag].IsZero() {
+			t.Errorf("Expected a date for '%s'", tag)
+		}
+	}
+	if _, found := dates["v1.0"]; found {
+		t.Error("Should not have fetched 'v1.0'")
+	}
+}
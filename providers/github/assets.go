@@ -0,0 +1,119 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package github
+
+import (
+	"fmt"
+	"github.com/DataDrake/cuppa/config"
+	"github.com/DataDrake/cuppa/results"
+	log "github.com/DataDrake/waterlog"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// AssetRegex matches release assets, capturing the tag and the filename
+	AssetRegex = regexp.MustCompile("github.com/[^/]+/[^/]+/releases/download/([^/]+)/([^/]+)$")
+	// ArchiveRegex matches release assets which are archives
+	ArchiveRegex = regexp.MustCompile("\\.(?:tar\\.(?:xz|bz2|gz|zst|lz)|tgz|zip)$")
+)
+
+// ArchiveExts are the archive types to choose between by default, in order of preference
+var ArchiveExts = []string{".tar.xz", ".tar.bz2", ".tar.gz", ".tar.zst", ".tar.lz", ".tgz", ".zip"}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name   string
+	URL    string
+	Digest string
+}
+
+// AssetPattern builds a pattern for matching the assets of other releases from an asset filename
+func AssetPattern(tag, file string) string {
+	escaped := regexp.QuoteMeta(file)
+	numeric := strings.Replace(tag, "_", ".", -1)
+	candidates := []string{tag, strings.TrimPrefix(tag, "v"), VersionRegex.FindString(tag), VersionRegex.FindString(numeric)}
+	for _, v := range candidates {
+		if q := regexp.QuoteMeta(v); len(v) > 0 && strings.Contains(escaped, q) {
+			return "^" + strings.Replace(escaped, q, ".+", 1) + "$"
+		}
+	}
+	return ""
+}
+
+// Pattern gets the asset pattern for a repo, preferring the config over the one from the query
+func Pattern(name, fromQuery string) *regexp.Regexp {
+	raw := config.Global.Github.Assets[name]
+	if len(raw) == 0 {
+		raw = fromQuery
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	pattern, err := regexp.Compile(raw)
+	if err != nil {
+		log.Warnf("Invalid asset pattern for %s: %s\n", name, err)
+		return nil
+	}
+	return pattern
+}
+
+// Preferred chooses the asset to download for a release, either the first asset matching the
+// pattern or, by default, a "<repo>-<version>" archive
+func Preferred(name, tag string, assets []Asset, pattern *regexp.Regexp) *Asset {
+	if pattern != nil {
+		for i := range assets {
+			if pattern.MatchString(assets[i].Name) {
+				return &assets[i]
+			}
+		}
+		return nil
+	}
+	prefix := strings.ToLower(path.Base(name) + "-" + strings.TrimPrefix(tag, "v"))
+	for _, ext := range ArchiveExts {
+		for i := range assets {
+			if strings.ToLower(assets[i].Name) == prefix+ext {
+				return &assets[i]
+			}
+		}
+	}
+	return nil
+}
+
+// NewResult creates a result for a tag, located at its preferred asset or its auto-generated archive
+func (repo Repo) NewResult(tag string, published time.Time, assets []Asset) *results.Result {
+	version, ok := repo.Tags.Version(tag)
+	if !ok {
+		return nil
+	}
+	archive := fmt.Sprintf(SourceFormat, repo.Host.Name, repo.Name, tag)
+	preferred := Preferred(repo.Name, tag, assets, repo.Pattern)
+	if preferred == nil {
+		return results.NewResult(repo.Name, version, archive, published)
+	}
+	r := results.NewResult(repo.Name, version, preferred.URL, published)
+	r.AddExtra("Archive", archive)
+	r.AddExtra("Digest", preferred.Digest)
+	for _, asset := range assets {
+		if asset.Name != preferred.Name && ArchiveRegex.MatchString(asset.Name) {
+			r.AddExtra("Asset "+asset.Name, asset.URL)
+		}
+	}
+	return r
+}
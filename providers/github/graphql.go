@@ -19,23 +19,27 @@ package github
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
-	"github.com/DataDrake/cuppa/config"
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	"github.com/DataDrake/cuppa/version"
 	log "github.com/DataDrake/waterlog"
 	"net/http"
 	"strings"
 	"time"
 )
 
-// GraphQLAPI is the location of the GraphQL Endpoint
-const GraphQLAPI = "https://api.github.com/graphql"
+// PageSize is the number of tags and releases requested for each page
+const PageSize = 100
 
-// RepoQueryFormat is the text format for the necessary GraphQL query
+// RepoQueryFormat is the text of the necessary GraphQL query, tags are ordered newest first
 const RepoQueryFormat = `
-query {
-    repository(owner: "%s", name: "%s") {
-        releases (last: %d) {
+query ($owner: String!, $name: String!, $first: Int!, $refs: String, $releases: String, $tags: String, $withReleases: Boolean!, $withRefs: Boolean!) {
+    repository(owner: $owner, name: $name) {
+        releases (first: $first, after: $releases, orderBy: {field: CREATED_AT, direction: DESC}) @include(if: $withReleases) {
+            pageInfo {
+                hasNextPage
+                endCursor
+            }
             nodes {
                 name
                 publishedAt
@@ -43,9 +47,20 @@ query {
                 tag {
                     name
                 }
+                releaseAssets (first: 50) {
+                    nodes {
+                        name
+                        downloadUrl
+                        digest
+                    }
+                }
             }
         }
-        refs (refPrefix: "refs/tags/", last: %d){
+        refs (refPrefix: "refs/tags/", query: $tags, first: $first, after: $refs, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) @include(if: $withRefs) {
+            pageInfo {
+                hasNextPage
+                endCursor
+            }
             nodes {
                 name
                 target {
@@ -66,79 +81,169 @@ query {
 
 // RepoQuery is the JSON payload for this request
 type RepoQuery struct {
-	Query string `json:"query"`
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// PageInfo is the JSON representation of a GraphQL cursor
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// Release is the JSON representation of a GitHub release
+type Release struct {
+	Name         string `json:"name"`
+	PublishedAt  string `json:"publishedAt"`
+	IsPrerelease bool   `json:"isPrerelease"`
+	Tag          struct {
+		Name string `json:"name"`
+	} `json:"tag"`
+	ReleaseAssets struct {
+		Nodes []struct {
+			Name        string `json:"name"`
+			DownloadURL string `json:"downloadUrl"`
+			Digest      string `json:"digest"`
+		} `json:"nodes"`
+	} `json:"releaseAssets"`
+}
+
+// Assets gets the files attached to this release
+func (rel Release) Assets() (assets []Asset) {
+	for _, node := range rel.ReleaseAssets.Nodes {
+		assets = append(assets, Asset{Name: node.Name, URL: node.DownloadURL, Digest: node.Digest})
+	}
+	return
+}
+
+// Ref is the JSON representation of a GitHub tag
+type Ref struct {
+	Name   string `json:"name"`
+	Target struct {
+		Date   string `json:"committedDate"`
+		Tagger struct {
+			Date string `json:"date"`
+		} `json:"tagger"`
+	} `json:"target"`
+}
+
+// Repository is the JSON representation of one page of releases and tags
+type Repository struct {
+	Releases struct {
+		PageInfo PageInfo  `json:"pageInfo"`
+		Nodes    []Release `json:"nodes"`
+	} `json:"releases"`
+	Refs struct {
+		PageInfo PageInfo `json:"pageInfo"`
+		Nodes    []Ref    `json:"nodes"`
+	} `json:"refs"`
 }
 
 // RepoQueryResult is the JSON payload of the response
 type RepoQueryResult struct {
 	Data struct {
-		Repository struct {
-			Releases struct {
-				Nodes []struct {
-					Name         string `json:"name"`
-					PublishedAt  string `json:"publishedAt"`
-					IsPrerelease bool   `json:"isPrerelease"`
-					Tag          struct {
-						Name string `json:"name"`
-					} `json:"tag"`
-				} `json:"nodes"`
-			} `json:"releases"`
-			Refs struct {
-				Nodes []struct {
-					Name   string `json:"name"`
-					Target struct {
-						Date   string `json:"committedDate"`
-						Tagger struct {
-							Date string `json:"date"`
-						} `json:"tagger"`
-					} `json:"target"`
-				} `json:"nodes"`
-			} `json:"refs"`
-		} `json:"repository"`
+		Repository *Repository `json:"repository"`
 	} `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
 }
 
-// Convert turns a RepoQueryResult into a Cuppa ResultSet
-func (rqr RepoQueryResult) Convert(name string) (rs *results.ResultSet) {
-	rs = results.NewResultSet(name)
+// Convert turns a tag into a Cuppa Result, using its release if it has one
+func (tag Ref) Convert(repo Repo, rel *Release) *results.Result {
+	var published time.Time
+	var assets []Asset
 	var err error
-	for _, tag := range rqr.Data.Repository.Refs.Nodes {
-		pre := false
-		found := false
-		var published time.Time
-		for _, node := range rqr.Data.Repository.Releases.Nodes {
-			if node.Tag.Name == tag.Name {
-				found = true
-				if node.IsPrerelease {
-					pre = true
-				}
-				published, _ = time.Parse(time.RFC3339, node.PublishedAt)
-			}
+	if rel != nil {
+		published, _ = time.Parse(time.RFC3339, rel.PublishedAt)
+		assets = rel.Assets()
+	} else if len(tag.Target.Date) > 0 {
+		published, _ = time.Parse(time.RFC3339, tag.Target.Date)
+	} else if len(tag.Target.Tagger.Date) > 0 {
+		published, err = time.Parse(time.RFC3339, tag.Target.Tagger.Date)
+		if err != nil {
+			published, _ = time.Parse("2006-01-02T15:04:05-07:00", tag.Target.Tagger.Date)
 		}
-		if pre {
-			continue
+	}
+	r := repo.NewResult(tag.Name, published, assets)
+	if r != nil && rel != nil && rel.IsPrerelease {
+		r.MarkUnstable(version.RC)
+	}
+	return r
+}
+
+// GetReleases gets up to "max" of the most recent tags for a given repo, one page at a time,
+// stopping early once "enough" stable releases have been found (unless "enough" is zero)
+func (c Provider) GetReleases(repo Repo, max, enough int) (rs *results.ResultSet, err error) {
+	names := strings.Split(repo.Name, "/")
+	vars := map[string]interface{}{
+		"owner": names[0],
+		"name":  names[1],
+		"first": PageSize,
+	}
+	// Narrow the tags of monorepos down to a single component on the server
+	if len(repo.Tags.Prefix) > 0 {
+		vars["tags"] = repo.Tags.Prefix
+	}
+	rs = results.NewResultSet(repo.Name)
+	rs.SetNormalizer(version.TagRules)
+	releases := make(map[string]Release)
+	var pending []Ref
+	moreRefs, moreReleases := true, true
+	for tags := 0; moreRefs || (moreReleases && len(pending) > 0); {
+		vars["withRefs"], vars["withReleases"] = moreRefs, moreReleases
+		var page *Repository
+		if page, err = repo.Host.query(vars); err != nil {
+			return
 		}
-		if !found {
-			if len(tag.Target.Date) > 0 {
-				published, _ = time.Parse(time.RFC3339, tag.Target.Date)
-			} else if len(tag.Target.Tagger.Date) > 0 {
-				published, err = time.Parse(time.RFC3339, tag.Target.Tagger.Date)
-				if err != nil {
-					published, _ = time.Parse("2006-01-02T15:04:05-07:00", tag.Target.Tagger.Date)
-				}
+		for _, node := range page.Releases.Nodes {
+			releases[node.Tag.Name] = node
+		}
+		// Releases are paged separately from tags, in case a tag was released long after it was made
+		moreReleases = moreReleases && page.Releases.PageInfo.HasNextPage && len(releases) < max
+		vars["releases"] = page.Releases.PageInfo.EndCursor
+		if moreRefs {
+			pending = append(pending, page.Refs.Nodes...)
+			tags += len(page.Refs.Nodes)
+			moreRefs = page.Refs.PageInfo.HasNextPage && tags < max
+			vars["refs"] = page.Refs.PageInfo.EndCursor
+		}
+		// Hold on to tags until their release is found, or there are no releases left to look through
+		var waiting []Ref
+		for _, tag := range pending {
+			if rel, found := releases[tag.Name]; found {
+				rs.AddResult(tag.Convert(repo, &rel))
+			} else if !moreReleases {
+				rs.AddResult(tag.Convert(repo, nil))
+			} else {
+				waiting = append(waiting, tag)
 			}
 		}
-		r := results.NewResult(name, tag.Name, fmt.Sprintf(SourceFormat, name, tag.Name), published)
-		rs.AddResult(r)
+		pending = waiting
+		if enough > 0 && rs.Len() >= enough {
+			moreRefs = false
+		}
 	}
 	return
 }
 
-// GetReleases gets a number of releases for a given repo
-func (c Provider) GetReleases(name string, max int) (rs *results.ResultSet, err error) {
-	names := strings.Split(name, "/")
+// query requests a single page of releases and tags, rotating tokens when one is rate limited
+func (h *Host) query(vars map[string]interface{}) (repo *Repository, err error) {
+	for {
+		token := h.Token()
+		repo, err = h.queryOnce(vars, token)
+		if err != results.RateLimited || !h.Rotate(token) {
+			return
+		}
+	}
+}
+
+// queryOnce requests a single page of releases and tags with a single token
+func (h *Host) queryOnce(vars map[string]interface{}, token string) (repo *Repository, err error) {
 	query := RepoQuery{
-		Query: fmt.Sprintf(RepoQueryFormat, names[0], names[1], max, max),
+		Query:     RepoQueryFormat,
+		Variables: vars,
 	}
 	var buff bytes.Buffer
 	enc := json.NewEncoder(&buff)
@@ -147,9 +252,9 @@ func (c Provider) GetReleases(name string, max int) (rs *results.ResultSet, err
 		err = results.Unavailable
 		return
 	}
-	req, _ := http.NewRequest("POST", GraphQLAPI, &buff)
-	if key := config.Global.Github.Key; len(key) > 0 {
-		req.Header["Authorization"] = []string{"token " + key}
+	req, _ := http.NewRequest("POST", h.GraphQL, &buff)
+	if len(token) > 0 {
+		req.Header["Authorization"] = []string{"token " + token}
 	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -167,6 +272,9 @@ func (c Provider) GetReleases(name string, max int) (rs *results.ResultSet, err
 		return
 	default:
 		err = results.Unavailable
+		if util.IsRateLimited(resp) {
+			err = results.RateLimited
+		}
 		return
 	}
 	// Decode response
@@ -177,6 +285,19 @@ func (c Provider) GetReleases(name string, max int) (rs *results.ResultSet, err
 		err = results.Unavailable
 		return
 	}
-	rs = rqr.Convert(name)
+	if repo = rqr.Data.Repository; repo == nil {
+		for _, e := range rqr.Errors {
+			log.Debugf("GraphQL error: %s\n", e.Message)
+			switch e.Type {
+			case "NOT_FOUND":
+				err = results.NotFound
+				return
+			case "RATE_LIMITED":
+				err = results.RateLimited
+				return
+			}
+		}
+		err = results.Unavailable
+	}
 	return
 }
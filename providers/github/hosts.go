@@ -0,0 +1,183 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package github
+
+import (
+	"github.com/DataDrake/cuppa/config"
+	log "github.com/DataDrake/waterlog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	// PublicHost is the name of the public GitHub instance
+	PublicHost = "github.com"
+	// PublicGraphQL is the location of the public GraphQL endpoint
+	PublicGraphQL = "https://api.github.com/graphql"
+	// PublicREST is the root of the public REST API
+	PublicREST = "https://api.github.com"
+)
+
+// PublicTokenVars are the environment variables that may hold a token for github.com
+var PublicTokenVars = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+
+// EnterpriseTokenVars are the environment variables that may hold a token for GitHub Enterprise
+var EnterpriseTokenVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+
+// Host is a GitHub or GitHub Enterprise Server instance, with its endpoints and tokens
+type Host struct {
+	Name    string
+	GraphQL string
+	REST    string
+
+	lock    sync.Mutex
+	tokens  []string
+	current int
+	helper  string
+}
+
+// newHost creates a Host, collecting tokens from the config and the environment, with a credential
+// helper to run only once those run out
+func newHost(name, graphql, rest string, keys []string, vars []string, helper string) *Host {
+	h := &Host{
+		Name:    name,
+		GraphQL: graphql,
+		REST:    strings.TrimSuffix(rest, "/"),
+		helper:  helper,
+	}
+	for _, key := range keys {
+		h.addToken(key)
+	}
+	for _, v := range vars {
+		h.addToken(os.Getenv(v))
+	}
+	return h
+}
+
+// runHelper adds the token from the credential helper, if it hasn't been run yet
+func (h *Host) runHelper() {
+	if len(h.helper) == 0 {
+		return
+	}
+	helper := h.helper
+	h.helper = ""
+	out, err := exec.Command("sh", "-c", helper).Output()
+	if err != nil {
+		log.Warnf("Credential helper for %s failed: %s\n", h.Name, err)
+		return
+	}
+	h.addToken(string(out))
+}
+
+func (h *Host) addToken(token string) {
+	token = strings.TrimSpace(token)
+	if len(token) == 0 {
+		return
+	}
+	for _, t := range h.tokens {
+		if t == token {
+			return
+		}
+	}
+	h.tokens = append(h.tokens, token)
+}
+
+// Token gets the token currently in use, if any remain
+func (h *Host) Token() string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.current >= len(h.tokens) {
+		h.runHelper()
+	}
+	if h.current >= len(h.tokens) {
+		return ""
+	}
+	return h.tokens[h.current]
+}
+
+// Rotate switches away from a rate-limited token, returning false once every token is exhausted
+func (h *Host) Rotate(token string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.current < len(h.tokens) && h.tokens[h.current] == token {
+		h.current++
+		log.Debugf("Rotating to the next token for %s\n", h.Name)
+	}
+	if h.current >= len(h.tokens) {
+		h.runHelper()
+	}
+	return h.current < len(h.tokens)
+}
+
+// Headers gets the headers for authenticating with the current token
+func (h *Host) Headers() map[string]string {
+	if token := h.Token(); len(token) > 0 {
+		return map[string]string{"Authorization": "token " + token}
+	}
+	return nil
+}
+
+var (
+	hosts     []*Host
+	hostsOnce sync.Once
+)
+
+// Hosts gets every known GitHub host, starting with github.com
+func Hosts() []*Host {
+	hostsOnce.Do(func() {
+		gh := config.Global.Github
+		keys := append([]string{gh.Key}, gh.Keys...)
+		hosts = append(hosts, newHost(PublicHost, PublicGraphQL, PublicREST, keys, PublicTokenVars, gh.Helper))
+		for _, e := range gh.Hosts {
+			graphql, rest := e.GraphQL, e.REST
+			if len(graphql) == 0 {
+				graphql = "https://" + e.Host + "/api/graphql"
+			}
+			if len(rest) == 0 {
+				rest = "https://" + e.Host + "/api/v3"
+			}
+			hosts = append(hosts, newHost(e.Host, graphql, rest, e.Keys, EnterpriseTokenVars, e.Helper))
+		}
+	})
+	return hosts
+}
+
+// IsHost checks if a host name is github.com or a configured GitHub Enterprise host, without
+// collecting any tokens
+func IsHost(name string) bool {
+	if name == PublicHost {
+		return true
+	}
+	for _, e := range config.Global.Github.Hosts {
+		if e.Host == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FindHost gets a known GitHub host by name
+func FindHost(name string) *Host {
+	for _, h := range Hosts() {
+		if h.Name == name {
+			return h
+		}
+	}
+	return nil
+}
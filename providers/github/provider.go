@@ -17,22 +17,40 @@
 package github
 
 import (
+	"github.com/DataDrake/cuppa/config"
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	log "github.com/DataDrake/waterlog"
 	"regexp"
 )
 
 const (
 	// SourceFormat is the format string for Github release tarballs
-	SourceFormat = "https://github.com/%s/archive/%s.tar.gz"
+	SourceFormat = "https://%s/%s/archive/%s.tar.gz"
+	// MaxTags is the default ceiling on the number of tags to read for a single repo
+	MaxTags = 500
+	// StableCandidates is the number of stable releases to find before looking for the latest
+	StableCandidates = 10
 )
 
 var (
 	// SourceRegex is the regex for Github sources
-	SourceRegex = regexp.MustCompile("github.com/([^/]+/[^/.]+)")
+	SourceRegex = regexp.MustCompile("(?:^|[/.@])(github.com)/([^/]+/[^/.]+)")
+	// HostRegex is the regex for sources on any host, checked against the configured GitHub Enterprise hosts
+	HostRegex = regexp.MustCompile("^(?:https?|git)://([^/]+)/([^/]+/[^/.]+)")
 	// VersionRegex is used to parse Github version numbers
 	VersionRegex = regexp.MustCompile("(?:\\d+\\.)*\\d+\\w*")
 )
 
+// Repo is a repository on a GitHub host, with the pattern for its preferred release assets
+// and the filter for the tags of its component
+type Repo struct {
+	Host    *Host
+	Name    string
+	Pattern *regexp.Regexp
+	Tags    util.TagFilter
+}
+
 // Provider is the upstream provider interface for github
 type Provider struct{}
 
@@ -43,15 +61,23 @@ func (c Provider) String() string {
 
 // Match checks to see if this provider can handle this kind of query
 func (c Provider) Match(query string) (params []string) {
-	if sm := SourceRegex.FindStringSubmatch(query); len(sm) > 1 {
-		params = sm[1:]
+	sm := SourceRegex.FindStringSubmatch(query)
+	if len(sm) < 3 {
+		if sm = HostRegex.FindStringSubmatch(query); len(sm) < 3 || sm[1] == PublicHost || !IsHost(sm[1]) {
+			return
+		}
+	}
+	params = []string{sm[2], "", sm[1]}
+	// Release assets are used to find the matching assets of other releases
+	if am := AssetRegex.FindStringSubmatch(query); len(am) > 2 {
+		params[1] = AssetPattern(am[1], am[2])
 	}
 	return
 }
 
 // Latest finds the newest release for a github package
 func (c Provider) Latest(params []string) (r *results.Result, err error) {
-	rs, err := c.GetReleases(params[0], 100)
+	rs, err := c.fetch(params, StableCandidates)
 	if err == nil {
 		r = rs.Last()
 	}
@@ -60,6 +86,35 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 
 // Releases finds all matching releases for a github package
 func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
-	rs, err = c.GetReleases(params[0], 100)
+	return c.fetch(params, 0)
+}
+
+// fetch gets the releases for a repo, stopping once "enough" stable releases are found
+func (c Provider) fetch(params []string, enough int) (rs *results.ResultSet, err error) {
+	repo := Repo{
+		Host: FindHost(PublicHost),
+		Name: params[0],
+		Tags: util.NewTagFilter(params[0]),
+	}
+	var fromQuery string
+	if len(params) > 2 {
+		fromQuery = params[1]
+		if h := FindHost(params[2]); h != nil {
+			repo.Host = h
+		}
+	}
+	repo.Pattern = Pattern(repo.Name, fromQuery)
+	max := config.Global.Github.MaxTags
+	if max <= 0 {
+		max = MaxTags
+	}
+	// GraphQL always requires a token, so use the REST API without one
+	if len(repo.Host.Token()) == 0 {
+		return c.GetRESTReleases(repo, max, enough)
+	}
+	if rs, err = c.GetReleases(repo, max, enough); err == results.Unavailable || err == results.RateLimited {
+		log.Debugf("Falling back to the REST API for: %s\n", repo.Name)
+		rs, err = c.GetRESTReleases(repo, max, enough)
+	}
 	return
 }
@@ -0,0 +1,138 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	"github.com/DataDrake/cuppa/version"
+	log "github.com/DataDrake/waterlog"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// RESTReleases is the format string for the REST releases endpoint
+	RESTReleases = "%s/repos/%s/releases?per_page=100"
+	// CloneFormat is the format string for the URL of a repo, used to list its tags
+	CloneFormat = "https://%s/%s.git"
+	// TagPrefix is the prefix of every tag reference
+	TagPrefix = "refs/tags/"
+	// PeeledSuffix marks the commit an annotated tag points to
+	PeeledSuffix = "^{}"
+)
+
+// RESTRelease is a JSON representation of a release from the REST API
+type RESTRelease struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
+		Name   string `json:"name"`
+		URL    string `json:"browser_download_url"`
+		Digest string `json:"digest"`
+	} `json:"assets"`
+}
+
+// GetRESTReleases gets every tag of a given repo and up to "max" of its releases, without needing an API
+// token. Tags are listed with "git ls-remote", since the REST API does not order or filter them, and are
+// left undated because only some of them have a release. Only the newest page of releases is read when
+// looking for "enough" releases, to spare the anonymous rate limit.
+func (c Provider) GetRESTReleases(repo Repo, max, enough int) (rs *results.ResultSet, err error) {
+	rels := make(map[string]RESTRelease)
+	url := fmt.Sprintf(RESTReleases, repo.Host.REST, repo.Name)
+	for len(url) > 0 && len(rels) < max {
+		var more []RESTRelease
+		if url, err = repo.Host.fetchPage(url, "releases", &more); err != nil {
+			return
+		}
+		for _, rel := range more {
+			if !rel.Draft {
+				rels[rel.TagName] = rel
+			}
+		}
+		if enough > 0 {
+			break
+		}
+	}
+	tags, err := lsRemote(repo)
+	if err != nil {
+		return
+	}
+	rs = results.NewResultSet(repo.Name)
+	rs.SetNormalizer(version.TagRules)
+	for _, tag := range tags {
+		var assets []Asset
+		rel, found := rels[tag]
+		for _, a := range rel.Assets {
+			assets = append(assets, Asset{a.Name, a.URL, a.Digest})
+		}
+		r := repo.NewResult(tag, time.Time{}, assets)
+		if r != nil && found && rel.Prerelease {
+			r.MarkUnstable(version.RC)
+		}
+		rs.AddResult(r)
+	}
+	return
+}
+
+// lsRemote lists the tags of a repo, or only those of its component for monorepos
+func lsRemote(repo Repo) (tags []string, err error) {
+	args := []string{"ls-remote", "--tags", fmt.Sprintf(CloneFormat, repo.Host.Name, repo.Name)}
+	if len(repo.Tags.Prefix) > 0 {
+		args = append(args, TagPrefix+repo.Tags.Prefix+"*")
+	}
+	var buff bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &buff
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if err = cmd.Run(); err != nil {
+		log.Debugf("Failed to list tags: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(&buff)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], TagPrefix) {
+			continue
+		}
+		tag := strings.TrimSuffix(strings.TrimPrefix(fields[1], TagPrefix), PeeledSuffix)
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return
+}
+
+// fetchPage requests a page from the REST API, rotating tokens when one is rate limited
+func (h *Host) fetchPage(url, kind string, out interface{}) (next string, err error) {
+	for {
+		token := h.Token()
+		next, err = util.FetchJSONPage(url, kind, h.Headers(), out)
+		if err != results.RateLimited || len(token) == 0 || !h.Rotate(token) {
+			return
+		}
+	}
+}
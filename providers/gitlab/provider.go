@@ -18,8 +18,11 @@ package gitlab
 
 import (
 	"fmt"
+	"github.com/DataDrake/cuppa/config"
 	"github.com/DataDrake/cuppa/results"
 	"github.com/DataDrake/cuppa/util"
+	log "github.com/DataDrake/waterlog"
+	neturl "net/url"
 	"regexp"
 	"strings"
 )
@@ -27,13 +30,17 @@ import (
 const (
 	// SourceFormat is the format string for GitLab release tarballs
 	SourceFormat = "https://%s/%s/-/archive/%s/%s.tar.gz"
+	// ReleasesEndpoint is the API endpoint URL for GitLab project releases
+	ReleasesEndpoint = "https://%s/api/v4/projects/%s/releases?per_page=100"
 	// TagsEndpoint is the API endpoint URL for GitLab project tags
-	TagsEndpoint = "https://%s/api/v4/projects/%s/repository/tags"
+	TagsEndpoint = "https://%s/api/v4/projects/%s/repository/tags?per_page=100"
+	// MaxPages is the largest number of pages to read from a single endpoint
+	MaxPages = 10
 )
 
 var (
 	// SourceRegex is the regex for GitLab sources
-	SourceRegex = regexp.MustCompile("https?://(gitlab[^/]+)/(.+/[^/.]+)/\\-/")
+	SourceRegex = regexp.MustCompile("https?://([^/]+)/(.+/[^/.]+)/\\-/")
 	// VersionRegex is used to parse GitLab version numbers
 	VersionRegex = regexp.MustCompile("(?:\\d+\\.)*\\d+\\w*")
 )
@@ -46,9 +53,22 @@ func (c Provider) String() string {
 	return "GitLab"
 }
 
+// IsInstance checks if a host is GitLab.com or a known GitLab instance
+func IsInstance(host string) bool {
+	if strings.HasPrefix(host, "gitlab") {
+		return true
+	}
+	for _, h := range config.Global.GitLab.Hosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
+
 // Match checks to see if this provider can handle this kind of query
 func (c Provider) Match(query string) (params []string) {
-	if sm := SourceRegex.FindStringSubmatch(query); len(sm) > 2 {
+	if sm := SourceRegex.FindStringSubmatch(query); len(sm) > 2 && IsInstance(sm[1]) {
 		params = sm[1:]
 	}
 	return
@@ -65,13 +85,40 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 
 // Releases finds all matching releases for a GitLab package
 func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
-	// Query the API
-	id := strings.Join(strings.Split(params[1], "/"), "%2f")
-	url := fmt.Sprintf(TagsEndpoint, params[0], id)
+	host, name := params[0], params[1]
+	id := strings.Join(strings.Split(name, "/"), "%2f")
+	headers := map[string]string{
+		"PRIVATE-TOKEN": config.Global.GitLab.Tokens[host],
+	}
+	// Query the API, going on with only tags when releases are disabled, private or unsupported
+	var rels Releases
+	url := fmt.Sprintf(ReleasesEndpoint, host, id)
+	for page := 0; page < MaxPages && len(url) > 0; page++ {
+		var more Releases
+		if url, err = util.FetchJSONPage(url, "releases", headers, &more); err != nil {
+			log.Debugf("Failed to get releases for %s, using tags only: %s\n", name, err)
+			rels = nil
+			break
+		}
+		rels = append(rels, more...)
+	}
+	// Narrow the tags of monorepos down to a single component on the server
+	filter := util.NewTagFilter(name)
 	var tags Tags
-	if err = util.FetchJSON(url, "releases", &tags); err != nil {
-		return
+	url = fmt.Sprintf(TagsEndpoint, host, id)
+	if len(filter.Prefix) > 0 {
+		url += "&search=" + neturl.QueryEscape("^"+filter.Prefix)
+	}
+	for page := 0; page < MaxPages && len(url) > 0; page++ {
+		var more Tags
+		if url, err = util.FetchJSONPage(url, "tags", headers, &more); err != nil {
+			return
+		}
+		tags = append(tags, more...)
+	}
+	rs = tags.Convert(host, name, rels, filter)
+	if rs.Len() == 0 {
+		err = results.NotFound
 	}
-	rs = tags.Convert(params[0], params[1])
 	return
 }
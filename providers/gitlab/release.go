@@ -0,0 +1,66 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitlab
+
+import (
+	"time"
+)
+
+// Source is a JSON representation of a GitLab release source archive
+type Source struct {
+	Format string `json:"format"`
+	URL    string `json:"url"`
+}
+
+// ProjectRelease is a JSON representation of an entry from the GitLab Releases API
+type ProjectRelease struct {
+	TagName    string `json:"tag_name"`
+	ReleasedAt string `json:"released_at"`
+	Upcoming   bool   `json:"upcoming_release"`
+	Assets     struct {
+		Sources []Source `json:"sources"`
+	} `json:"assets"`
+}
+
+// Released gets the time this release was published
+func (pr ProjectRelease) Released() time.Time {
+	released, _ := time.Parse(time.RFC3339, pr.ReleasedAt)
+	return released
+}
+
+// Tarball gets the location of the "tar.gz" source archive for this release
+func (pr ProjectRelease) Tarball() string {
+	for _, src := range pr.Assets.Sources {
+		if src.Format == "tar.gz" {
+			return src.URL
+		}
+	}
+	return ""
+}
+
+// Releases is a set of one or more GitLab releases
+type Releases []ProjectRelease
+
+// Find gets the release for a tag, if there is one
+func (rels Releases) Find(tag string) *ProjectRelease {
+	for i := range rels {
+		if rels[i].TagName == tag {
+			return &rels[i]
+		}
+	}
+	return nil
+}
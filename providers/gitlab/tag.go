@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
 )
 
 // Commit is a JSON representation of a GitLab commit
@@ -29,27 +30,32 @@ type Commit struct {
 	AuthoredDate string `json:"authored_date"`
 }
 
-// Release is a JSON representation of a GitLab tag release
-type Release struct {
-	TagName string `json:"tag_name"`
-}
-
 // Tag is a JSON representation of a GitLab tag
 type Tag struct {
-	Name    string  `json:"name"`
-	Commit  Commit  `json:"commit"`
-	Release Release `json:"release"`
+	Name   string `json:"name"`
+	Commit Commit `json:"commit"`
 }
 
-// Convert turns a GitLab tag into a Cuppa result
-func (gl Tag) Convert(host, name string) *results.Result {
+// Convert turns a GitLab tag into a Cuppa result, using the details of its release if there is one
+func (gl Tag) Convert(host, name string, rel *ProjectRelease, filter util.TagFilter) *results.Result {
+	if rel != nil && rel.Upcoming {
+		return nil
+	}
+	version, ok := filter.Version(gl.Name)
+	if !ok {
+		return nil
+	}
 	published, _ := time.Parse(time.RFC3339, gl.Commit.AuthoredDate)
-	file := fmt.Sprintf("%s-%s", strings.Split(name, "/")[1], gl.Name)
+	pieces := strings.Split(name, "/")
+	file := fmt.Sprintf("%s-%s", pieces[len(pieces)-1], strings.Replace(gl.Name, "/", "-", -1))
 	loc := fmt.Sprintf(SourceFormat, host, name, gl.Name, file)
-	version := gl.Release.TagName
-	if len(version) == 0 {
-		vs := strings.Split(gl.Name, "-")
-		version = vs[len(vs)-1]
+	if rel != nil {
+		if released := rel.Released(); !released.IsZero() {
+			published = released
+		}
+		if tarball := rel.Tarball(); len(tarball) > 0 {
+			loc = tarball
+		}
 	}
 	return results.NewResult(name, version, loc, published)
 }
@@ -18,17 +18,22 @@ package gitlab
 
 import (
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	"github.com/DataDrake/cuppa/version"
 )
 
 // Tags is a set of one or more GitLab tags
 type Tags []Tag
 
-// Convert turns a GitLab result set into a Cuppa ResultSet
-func (gls Tags) Convert(host, name string) *results.ResultSet {
+// Convert turns a GitLab result set into a Cuppa ResultSet, skipping upcoming releases
+// and the tags of other components
+func (gls Tags) Convert(host, name string, rels Releases, filter util.TagFilter) *results.ResultSet {
 	rs := results.NewResultSet(name)
-	for _, rel := range gls {
-		r := rel.Convert(host, name)
-		rs.AddResult(r)
+	rs.SetNormalizer(version.TagRules)
+	for _, tag := range gls {
+		if r := tag.Convert(host, name, rels.Find(tag.Name), filter); r != nil {
+			rs.AddResult(r)
+		}
 	}
 	return rs
 }
@@ -19,12 +19,12 @@ package gnome
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/DataDrake/cuppa/config"
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
 	log "github.com/DataDrake/waterlog"
 	"net/http"
 	"regexp"
-	"strconv"
-	"strings"
 	"time"
 )
 
@@ -38,6 +38,16 @@ const (
 // TarballRegex matches GNOME sources
 var TarballRegex = regexp.MustCompile("https?://(?:ftp.gnome.org/pub/gnome|download.gnome.org)/sources/(.+?)/.*")
 
+// TarballExts are the tarball types listed in cache.json, in order of preference
+var TarballExts = []string{"tar.xz", "tar.gz", "tar.bz2"}
+
+// ExtraFiles maps the names of additional files in a Result to their type in cache.json
+var ExtraFiles = map[string]string{
+	"Checksums": "sha256sum",
+	"Changes":   "changes",
+	"News":      "news",
+}
+
 // Provider is the upstream provider interface for GNOME
 type Provider struct{}
 
@@ -63,7 +73,7 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 	return
 }
 
-// Releases finds all matching releases for a rubygems package
+// Releases finds all matching releases for a GNOME package
 func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 	name := params[0]
 	// Query the API
@@ -107,36 +117,36 @@ func Merge(name string, srcs, vs map[string]interface{}) (rs *results.ResultSet)
 	if srcs[name] == nil || vs[name] == nil {
 		return
 	}
+	policy := config.Global.GNOME.Policies[name]
+	if !KnownPolicy(policy) {
+		log.Warnf("Unknown stability policy for %s: %s\n", name, policy)
+		policy = PolicyAuto
+	}
 	for _, v := range vs[name].([]interface{}) {
-		pieces := strings.Split(v.(string), ".")
-		if len(pieces) < 2 {
-			continue
-		}
-		minor, err := strconv.Atoi(pieces[1])
-		if err != nil {
-			continue
-		}
-		// Filter out unstable releases
-		if minor%2 == 1 {
-			continue
-		}
-		files := srcs[name].(map[string]interface{})[v.(string)].(map[string]interface{})
-		if len(files) == 0 {
+		files, ok := srcs[name].(map[string]interface{})[v.(string)].(map[string]interface{})
+		if !ok || len(files) == 0 {
 			continue
 		}
 		// get location of tarball
 		var location string
-		switch {
-		case files["tar.xz"] != nil:
-			location = fmt.Sprintf(SourceFormat, name, files["tar.xz"].(string))
-		case files["tar.gz"] != nil:
-			location = fmt.Sprintf(SourceFormat, name, files["tar.gz"].(string))
-		case files["tar.bz2"] != nil:
-			location = fmt.Sprintf(SourceFormat, name, files["tar.bz2"].(string))
-		default:
+		for _, ext := range TarballExts {
+			if file, ok := files[ext].(string); ok {
+				location = fmt.Sprintf(SourceFormat, name, file)
+				break
+			}
+		}
+		if len(location) == 0 {
 			continue
 		}
 		r := results.NewResult(name, v.(string), location, time.Time{})
+		if !Stable(policy, v.(string)) {
+			r.MarkUnstable(version.Dev)
+		}
+		for key, kind := range ExtraFiles {
+			if file, ok := files[kind].(string); ok {
+				r.AddExtra(key, fmt.Sprintf(SourceFormat, name, file))
+			}
+		}
 		rs.AddResult(r)
 	}
 	return
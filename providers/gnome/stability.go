@@ -0,0 +1,68 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gnome
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// PolicyAuto uses odd/even minor versions before GNOME 40 and the GNOME 40 scheme afterwards
+	PolicyAuto = "auto"
+	// PolicyOddEven treats every odd minor version as unstable
+	PolicyOddEven = "odd-even"
+	// PolicyNumeric treats every fully numeric version as stable, like "44.1" but not "44.rc"
+	PolicyNumeric = "numeric"
+)
+
+// ModernMajor is the first major version using the GNOME 40 versioning scheme
+const ModernMajor = 40
+
+// KnownPolicy checks if a stability policy is one of the supported policies, or empty for PolicyAuto
+func KnownPolicy(policy string) bool {
+	switch policy {
+	case "", PolicyAuto, PolicyOddEven, PolicyNumeric:
+		return true
+	}
+	return false
+}
+
+// Stable checks if a version is a stable release under a stability policy, defaulting to PolicyAuto
+func Stable(policy, version string) bool {
+	pieces := strings.Split(version, ".")
+	nums := make([]int, len(pieces))
+	for i, piece := range pieces {
+		n, err := strconv.Atoi(piece)
+		if err != nil {
+			// Pre-releases like "44.alpha", "44.rc" or "3.37.beta"
+			return false
+		}
+		nums[i] = n
+	}
+	switch policy {
+	case PolicyNumeric:
+		return true
+	case PolicyOddEven:
+		return len(nums) > 1 && nums[1]%2 == 0
+	default:
+		if nums[0] >= ModernMajor {
+			return true
+		}
+		return len(nums) > 1 && nums[1]%2 == 0
+	}
+}
@@ -0,0 +1,64 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gnu
+
+import (
+	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	log "github.com/DataDrake/waterlog"
+	"github.com/jlaffaye/ftp"
+)
+
+// ListFTP gets the files in a directory of an FTP mirror
+func ListFTP(host, dir string) (entries []util.Entry, err error) {
+	client, err := ftp.Dial(host)
+	if err != nil {
+		log.Debugf("Failed to connect to FTP server: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	defer client.Quit()
+	if err = client.Login("anonymous", "anonymous"); err != nil {
+		log.Debugf("Failed to login to FTP server: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	list, err := client.List(dir)
+	if err != nil {
+		log.Debugf("FTP Error: %s\n", err.Error())
+		err = results.NotFound
+		return
+	}
+	for _, entry := range list {
+		if entry.Type != ftp.EntryTypeFile {
+			continue
+		}
+		entries = append(entries, util.Entry{Name: entry.Name, Time: entry.Time})
+	}
+	return
+}
+
+// ListHTTP gets the files in an HTTP directory listing
+func ListHTTP(url string) (entries []util.Entry, err error) {
+	list, err := util.ListHTTP(url)
+	for _, entry := range list {
+		if !entry.Dir {
+			entries = append(entries, entry)
+		}
+	}
+	return
+}
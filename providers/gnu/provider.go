@@ -18,9 +18,10 @@ package gnu
 
 import (
 	"fmt"
+	"github.com/DataDrake/cuppa/config"
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
 	log "github.com/DataDrake/waterlog"
-	"github.com/jlaffaye/ftp"
 	"regexp"
 	"sort"
 )
@@ -28,13 +29,21 @@ import (
 const (
 	// MirrorsFTP is the host to use as a GNU mirror
 	MirrorsFTP = "mirrors.rit.edu:21"
-	// GNUFormat is the format string for GNU sources
-	GNUFormat = "https://mirrors.rit.edu/gnu/%s/%s"
+	// MirrorsHTTP is the format string for listing a GNU directory when FTP is unavailable
+	MirrorsHTTP = "https://ftpmirror.gnu.org/%s/"
+	// GNUFormat is the format string for GNU sources, redirected to a nearby mirror whichever one was listed
+	GNUFormat = "https://ftpmirror.gnu.org/%s/%s"
+	// SavannahHTTP is the format string for listing a Savannah release directory
+	SavannahHTTP = "https://download.savannah.%s.org/releases/%s/"
+	// SavannahFormat is the format string for Savannah sources
+	SavannahFormat = "https://download.savannah.%s.org/releases/%s/%s"
 )
 
 var (
 	// MirrorsRegex is a regex for a GNU mirror source
 	MirrorsRegex = regexp.MustCompile("(?:https?|ftp)://[^\\/]+/gnu/(.+)/[^\\/]+$")
+	// SavannahRegex is a regex for a GNU Savannah or nongnu.org source
+	SavannahRegex = regexp.MustCompile("(?:https?|ftp)://(?:download|download-mirror)\\.savannah\\.(gnu|nongnu)\\.org/releases/(.+)/[^\\/]+$")
 	// TarballRegex is a regex for finding tarball files
 	TarballRegex = regexp.MustCompile("^(.+)-(.+)\\.tar\\..+z$")
 )
@@ -49,6 +58,10 @@ func (c Provider) String() string {
 
 // Match checks to see if this provider can handle this kind of query
 func (c Provider) Match(query string) (params []string) {
+	if sm := SavannahRegex.FindStringSubmatch(query); len(sm) > 2 {
+		params = append(params, sm[2], sm[1])
+		return
+	}
 	if sm := MirrorsRegex.FindStringSubmatch(query); len(sm) > 1 {
 		params = sm[1:]
 	}
@@ -67,30 +80,36 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 // Releases finds all matching releases for a GNU package
 func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 	name := params[0]
-	client, err := ftp.Dial(MirrorsFTP)
-	if err != nil {
-		log.Debugf("Failed to connect to FTP server: %s\n", err)
-		err = results.Unavailable
-		return
-	}
-	if err = client.Login("anonymous", "anonymous"); err != nil {
-		log.Debugf("Failed to login to FTP server: %s\n", err)
-		err = results.Unavailable
-		return
+	var entries []util.Entry
+	var path string
+	if len(params) > 1 {
+		// Savannah, optionally falling back to a configured FTP mirror
+		site := params[1]
+		entries, err = ListHTTP(fmt.Sprintf(SavannahHTTP, site, name))
+		if err == results.Unavailable && len(config.Global.GNU.Savannah) > 0 {
+			entries, err = ListFTP(config.Global.GNU.Savannah, "releases/"+name)
+		}
+		path = fmt.Sprintf(SavannahFormat, site, name, "")
+	} else {
+		// GNU, preferring FTP and falling back to ftpmirror.gnu.org
+		mirror := config.Global.GNU.Mirror
+		if len(mirror) == 0 {
+			mirror = MirrorsFTP
+		}
+		entries, err = ListFTP(mirror, "gnu/"+name)
+		if err == results.Unavailable {
+			log.Debugf("Falling back to HTTP listing for: %s\n", name)
+			entries, err = ListHTTP(fmt.Sprintf(MirrorsHTTP, name))
+		}
+		path = fmt.Sprintf(GNUFormat, name, "")
 	}
-	entries, err := client.List("gnu" + "/" + name)
 	if err != nil {
-		log.Debugf("FTP Error: %s\n", err.Error())
-		err = results.NotFound
 		return
 	}
 	rs = results.NewResultSet(name)
 	for _, entry := range entries {
-		if entry.Type != ftp.EntryTypeFile {
-			continue
-		}
 		if sm := TarballRegex.FindStringSubmatch(entry.Name); len(sm) > 2 {
-			r := results.NewResult(sm[1], sm[2], fmt.Sprintf(GNUFormat, name, entry.Name), entry.Time)
+			r := results.NewResult(sm[1], sm[2], path+entry.Name, entry.Time)
 			rs.AddResult(r)
 		}
 	}
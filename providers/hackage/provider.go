@@ -22,17 +22,24 @@ import (
 	"github.com/DataDrake/cuppa/util"
 	log "github.com/DataDrake/waterlog"
 	"io/ioutil"
-	"net/http"
 	"regexp"
+	"strings"
+	"sync"
 )
 
 const (
+	// CabalAPI is the format string for a revision of a Hackage package description
+	CabalAPI = "https://hackage.haskell.org/package/%s-%s/revision/%d.cabal"
+	// RevisionsAPI is the format string for the Hackage Revisions API
+	RevisionsAPI = "https://hackage.haskell.org/package/%s-%s/revisions/"
 	// TarballAPI is the format string for the Hackage Tarball API
 	TarballAPI = "https://hackage.haskell.org/package/%s-%s/%s-%s.tar.gz"
 	// UploadTimeAPI is the format string for the Hackage Upload Time API
 	UploadTimeAPI = "https://hackage.haskell.org/package/%s-%s/upload-time"
 	// VersionsAPI is the format string for the Hackage Versions API
 	VersionsAPI = "https://hackage.haskell.org/package/%s/preferred"
+	// MaxRequests is the largest number of concurrent requests to make to Hackage
+	MaxRequests = 8
 )
 
 // TarballRegex matches HAckage tarballs
@@ -58,7 +65,7 @@ func (c Provider) Match(query string) (params []string) {
 func (c Provider) Latest(params []string) (r *results.Result, err error) {
 	rs, err := c.Releases(params)
 	if err == nil {
-		r = rs.First()
+		r = rs.Last()
 	}
 	return
 }
@@ -72,31 +79,66 @@ func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 		return
 	}
 	// Process releases
+	preferred := versions.Preferred()
+	found := make([]*Release, len(preferred))
+	limit := make(chan struct{}, MaxRequests)
+	var wg sync.WaitGroup
+	for i, v := range preferred {
+		wg.Add(1)
+		go func(i int, v string) {
+			defer wg.Done()
+			limit <- struct{}{}
+			found[i] = fetchRelease(name, v)
+			<-limit
+		}(i, v)
+	}
+	wg.Wait()
 	var hrs Releases
-	for _, v := range versions.Normal {
-		hr := Release{
-			name:    name,
-			version: v,
-		}
-		r, err := http.Get(fmt.Sprintf(UploadTimeAPI, name, v))
-		if err != nil {
-			log.Debugf("Failed to get upload time: %s\n", err)
-			continue
-		}
-		defer r.Body.Close()
-		dateRaw, err := ioutil.ReadAll(r.Body)
-		if err != nil {
-			log.Debugf("Failed to read response: %s\n", err)
-			continue
+	for _, hr := range found {
+		if hr != nil {
+			hrs.Releases = append(hrs.Releases, *hr)
 		}
-		hr.released = string(dateRaw)
-		hrs.Releases = append(hrs.Releases, hr)
 	}
 	if len(hrs.Releases) == 0 {
 		err = results.NotFound
 		return
 	}
 	rs = hrs.Convert(name)
-	err = nil
 	return
 }
+
+// fetchRelease gets the upload time and latest revision of a single release
+func fetchRelease(name, version string) *Release {
+	hr := &Release{
+		name:    name,
+		version: version,
+	}
+	var revs []Revision
+	if err := util.FetchJSON(fmt.Sprintf(RevisionsAPI, name, version), "revisions", &revs); err == nil {
+		for _, rev := range revs {
+			if rev.Number == 0 {
+				hr.released = rev.Time
+			}
+			if rev.Number > hr.revision {
+				hr.revision = rev.Number
+			}
+		}
+	}
+	if len(hr.released) > 0 {
+		return hr
+	}
+	// Fall back to the upload time of the original release
+	body, err := util.Fetch(fmt.Sprintf(UploadTimeAPI, name, version), "upload time")
+	if err != nil {
+		log.Debugf("Failed to get upload time: %s\n", err)
+		return nil
+	}
+	defer body.Close()
+	dateRaw, err := ioutil.ReadAll(body)
+	if err != nil {
+		log.Debugf("Failed to read response: %s\n", err)
+		return nil
+	}
+	hr.released = strings.TrimSpace(string(dateRaw))
+	return hr
+}
@@ -19,12 +19,39 @@ package hackage
 import (
 	"fmt"
 	"github.com/DataDrake/cuppa/results"
+	"strconv"
 	"time"
 )
 
 // Versions is a JSON representation of Hackage release version numbers
 type Versions struct {
-	Normal []string `json:"normal-version"`
+	Normal      []string `json:"normal-version"`
+	Deprecated  []string `json:"deprecated-version"`
+	Unpreferred []string `json:"unpreferred-version"`
+}
+
+// Preferred gets the normal versions, falling back to unpreferred ones, but never deprecated ones
+func (vs Versions) Preferred() (preferred []string) {
+	deprecated := make(map[string]bool)
+	for _, v := range vs.Deprecated {
+		deprecated[v] = true
+	}
+	candidates := vs.Normal
+	if len(candidates) == 0 {
+		candidates = vs.Unpreferred
+	}
+	for _, v := range candidates {
+		if !deprecated[v] {
+			preferred = append(preferred, v)
+		}
+	}
+	return
+}
+
+// Revision is a JSON representation of a revision of a Hackage package description
+type Revision struct {
+	Number int    `json:"number"`
+	Time   string `json:"time"`
 }
 
 // Release is a local representation of a Hackage release
@@ -32,11 +59,20 @@ type Release struct {
 	name     string
 	released string
 	version  string
+	revision int
 }
 
 // Convert turns a Hackage release into a Cuppa result
 func (hr *Release) Convert() *results.Result {
-	pub, _ := time.Parse(time.UnixDate, hr.released)
+	pub, err := time.Parse(time.RFC3339, hr.released)
+	if err != nil {
+		pub, _ = time.Parse(time.UnixDate, hr.released)
+	}
 	loc := fmt.Sprintf(TarballAPI, hr.name, hr.version, hr.name, hr.version)
-	return results.NewResult(hr.name, hr.version, loc, pub)
+	r := results.NewResult(hr.name, hr.version, loc, pub)
+	if hr.revision > 0 {
+		r.AddExtra("Revision", strconv.Itoa(hr.revision))
+		r.AddExtra("Cabal", fmt.Sprintf(CabalAPI, hr.name, hr.version, hr.revision))
+	}
+	return r
 }
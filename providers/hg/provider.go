@@ -0,0 +1,114 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package hg
+
+import (
+	"fmt"
+	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	"github.com/DataDrake/cuppa/version"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// TagsAPI is the format string for the list of tags from hgweb
+	TagsAPI = "%s/json-tags"
+	// SourceFormat is the format string for hgweb tarballs
+	SourceFormat = "%s/archive/%s.tar.gz"
+)
+
+var (
+	// HostRegex matches sources from hgweb hosts like "hg.mozilla.org"
+	HostRegex = regexp.MustCompile("^(https?://hg\\.[^/]+/.+?)/(?:archive|raw-file|file|rev)/.+$")
+	// HeptapodRegex matches sources from Heptapod instances
+	HeptapodRegex = regexp.MustCompile("^(https?://[^/]*heptapod[^/]*/.+?)/-/.+$")
+)
+
+// Tags is the JSON representation of the list of tags from hgweb
+type Tags struct {
+	Tags []struct {
+		Tag string `json:"tag"`
+		// Date is a unix timestamp followed by a timezone offset
+		Date []float64 `json:"date"`
+	} `json:"tags"`
+}
+
+// Provider provides a common interface for each of the backend providers
+type Provider struct{}
+
+// String returns the name of this provider
+func (p Provider) String() string {
+	return "Mercurial"
+}
+
+// Match checks to see if this provider can handle this kind of query
+func (p Provider) Match(query string) (params []string) {
+	if strings.HasPrefix(query, "hg|") {
+		params = append(params, strings.TrimPrefix(query, "hg|"))
+		return
+	}
+	if sm := HostRegex.FindStringSubmatch(query); len(sm) > 1 {
+		params = append(params, sm[1])
+		return
+	}
+	if sm := HeptapodRegex.FindStringSubmatch(query); len(sm) > 1 {
+		params = append(params, sm[1])
+	}
+	return
+}
+
+// Latest finds the newest release for a Mercurial package
+func (p Provider) Latest(params []string) (r *results.Result, err error) {
+	rs, err := p.Releases(params)
+	if err == nil {
+		r = rs.Last()
+	}
+	return
+}
+
+// Releases finds all matching releases for a Mercurial package
+func (p Provider) Releases(params []string) (rs *results.ResultSet, err error) {
+	name := strings.TrimSuffix(params[0], "/")
+	repoName := path.Base(name)
+	// Query hgweb
+	var tags Tags
+	if err = util.FetchJSON(fmt.Sprintf(TagsAPI, name), "tags", &tags); err != nil {
+		return
+	}
+	// Convert tags to releases
+	rs = results.NewResultSet(name)
+	rs.SetNormalizer(version.TagRules)
+	for _, tag := range tags.Tags {
+		if tag.Tag == "tip" {
+			continue
+		}
+		var date time.Time
+		if len(tag.Date) > 0 {
+			date = time.Unix(int64(tag.Date[0]), 0).UTC()
+		}
+		location := fmt.Sprintf(SourceFormat, name, url.PathEscape(tag.Tag))
+		rs.AddResult(results.NewResult(repoName, tag.Tag, location, date))
+	}
+	if rs.Len() == 0 {
+		err = results.NotFound
+	}
+	return
+}
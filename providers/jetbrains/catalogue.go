@@ -0,0 +1,151 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package jetbrains
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/DataDrake/cuppa/util"
+	log "github.com/DataDrake/waterlog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ProductsAPI is the location of the JetBrains Products API
+	ProductsAPI = "https://data.services.jetbrains.com/products?fields=code,name"
+	// CatalogueFile is the name of the cached catalogue in the cuppa cache directory
+	CatalogueFile = "jetbrains.json"
+	// CatalogueTTL is how long a cached catalogue is used before it is refreshed
+	CatalogueTTL = 24 * time.Hour
+)
+
+// FilenameRegex matches the product part of a JetBrains download filename
+var FilenameRegex = regexp.MustCompile("^(.+?)-\\d")
+
+// Product is a JSON representation of a JetBrains product
+type Product struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Catalogue maps lowercase download filename prefixes to JetBrains product codes
+type Catalogue map[string]string
+
+var (
+	catalogue     Catalogue
+	catalogueLock sync.Mutex
+)
+
+// Code finds the product code for a lowercase download filename prefix
+func Code(name string) (code string, err error) {
+	catalogueLock.Lock()
+	defer catalogueLock.Unlock()
+	if catalogue == nil {
+		if catalogue = loadCatalogue(); catalogue == nil {
+			if catalogue, err = fetchCatalogue(); err != nil {
+				return
+			}
+			saveCatalogue(catalogue)
+		}
+	}
+	code = catalogue[name]
+	return
+}
+
+// fetchCatalogue builds a Catalogue from the filenames of the latest downloads of every product
+func fetchCatalogue() (c Catalogue, err error) {
+	var products []Product
+	if err = util.FetchJSON(ProductsAPI, "products", &products); err != nil {
+		return
+	}
+	codes := make([]string, 0, len(products))
+	for _, p := range products {
+		codes = append(codes, p.Code)
+	}
+	var jbs Releases
+	if err = util.FetchJSON(fmt.Sprintf(LatestAPI, strings.Join(codes, ",")), "latest", &jbs); err != nil {
+		return
+	}
+	c = make(Catalogue)
+	for code, rels := range jbs {
+		for _, rel := range rels {
+			for _, d := range rel.Downloads {
+				if sm := FilenameRegex.FindStringSubmatch(path.Base(d.Link)); len(sm) > 1 {
+					c[strings.ToLower(sm[1])] = code
+				}
+			}
+		}
+	}
+	return
+}
+
+func cataloguePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cuppa", CatalogueFile)
+}
+
+// loadCatalogue reads the cached Catalogue, if it exists and is not stale
+func loadCatalogue() (c Catalogue) {
+	p := cataloguePath()
+	if len(p) == 0 {
+		return
+	}
+	info, err := os.Stat(p)
+	if err != nil || time.Since(info.ModTime()) > CatalogueTTL {
+		return
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if err = json.NewDecoder(f).Decode(&c); err != nil {
+		log.Debugf("Failed to read cached catalogue: %s\n", err)
+		c = nil
+	}
+	return
+}
+
+// saveCatalogue writes the Catalogue to the cache
+func saveCatalogue(c Catalogue) {
+	p := cataloguePath()
+	if len(p) == 0 {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		log.Debugf("Failed to create cache directory: %s\n", err)
+		return
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		log.Debugf("Failed to cache catalogue: %s\n", err)
+		return
+	}
+	defer f.Close()
+	if err = json.NewEncoder(f).Encode(c); err != nil {
+		log.Debugf("Failed to cache catalogue: %s\n", err)
+	}
+}
@@ -24,30 +24,11 @@ import (
 	"strings"
 )
 
-// ReleaseCodes provides a mapping between JetBrains products and their API codename
-var ReleaseCodes = map[string]string{
-	"appcode":              "AC",
-	"clion":                "CL",
-	"datagrip":             "DG",
-	"goglang":              "GO",
-	"ideaiu":               "IIU",
-	"ideaic":               "IIC",
-	"phpstorm":             "PS",
-	"pycharm-professional": "PCP",
-	"pycharm-ce":           "PCC",
-	"pycharm-community":    "PCC",
-	"pycharm-edu":          "PCE",
-	"rider":                "RD",
-	"rubymine":             "RM",
-	"upsource":             "US",
-	"webstorm":             "WS",
-}
-
 const (
-	// ReleasesAPI is the format string for the JetBrains Releases API
-	ReleasesAPI = "https://data.services.jetbrains.com/products/releases?code=%s"
+	// ReleasesAPI is the format string for the JetBrains Releases API, including RCs and EAPs
+	ReleasesAPI = "https://data.services.jetbrains.com/products/releases?code=%s&type=release,rc,eap"
 	// LatestAPI is the format string for the JetBrains Releases API when asking for latest
-	LatestAPI = "https://data.services.jetbrains.com/products/releases?code=%s&latest=true"
+	LatestAPI = "https://data.services.jetbrains.com/products/releases?code=%s&type=release,rc,eap&latest=true"
 )
 
 // SourceRegex matches JetBrains sources
@@ -75,7 +56,7 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 	if err != nil {
 		return
 	}
-	r = rs.First()
+	r = rs.Last()
 	return
 }
 
@@ -86,17 +67,24 @@ func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 
 func (c Provider) fetchReleases(api, kind string, params []string) (rs *results.ResultSet, err error) {
 	name := params[0]
+	code, err := Code(name)
+	if err != nil {
+		return
+	}
+	if len(code) == 0 {
+		err = results.NotFound
+		return
+	}
 	// Query the API
-	code := ReleaseCodes[name]
 	var jbs Releases
 	url := fmt.Sprintf(api, code)
 	if err = util.FetchJSON(url, kind, &jbs); err != nil {
 		return
 	}
-	if jbs[code] == nil || len(jbs[code]) == 0 {
+	if len(jbs[code]) == 0 {
 		err = results.NotFound
 		return
 	}
-	rs = jbs.Convert(name)
+	rs = jbs.Convert(name, code)
 	return
 }
@@ -18,9 +18,14 @@ package jetbrains
 
 import (
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
+	"sort"
 	"time"
 )
 
+// Platforms are the preferred download platforms, in order
+var Platforms = []string{"linuxWithoutJDK", "linux"}
+
 // Download is a JSON representation of a JetBrains downloadable source
 type Download struct {
 	ChecksumLink string `json:"checksumLink"`
@@ -41,12 +46,32 @@ type Release struct {
 
 // Convert turns a JetBrains release into a Cuppa result
 func (jb Release) Convert() *results.Result {
-	published, _ := time.Parse("2006-01-02", jb.Date)
-	if d, ok := jb.Downloads["linuxWithoutJDK"]; ok {
-		return results.NewResult("", jb.Version, d.Link, published)
+	if len(jb.Downloads) == 0 {
+		return nil
+	}
+	platforms := make([]string, 0, len(jb.Downloads))
+	for platform := range jb.Downloads {
+		platforms = append(platforms, platform)
 	}
-	if d, ok := jb.Downloads["linux"]; ok {
-		return results.NewResult("", jb.Version, d.Link, published)
+	sort.Strings(platforms)
+	// Use the preferred platform, or any if none are available
+	chosen := platforms[0]
+	for _, platform := range Platforms {
+		if _, ok := jb.Downloads[platform]; ok {
+			chosen = platform
+			break
+		}
+	}
+	published, _ := time.Parse("2006-01-02", jb.Date)
+	d := jb.Downloads[chosen]
+	r := results.NewResult("", jb.Version, d.Link, published)
+	// Early access versions look like final releases, like "2024.1" with a type of "eap"
+	r.MarkUnstable(version.Classify(jb.Type))
+	r.AddExtra("Checksum", d.ChecksumLink)
+	for _, platform := range platforms {
+		if platform != chosen {
+			r.AddExtra(platform, jb.Downloads[platform].Link)
+		}
 	}
-	return nil
+	return r
 }
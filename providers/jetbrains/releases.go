@@ -18,15 +18,17 @@ package jetbrains
 
 import (
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
 )
 
 // Releases is a collection of JetBrains releases
 type Releases map[string][]Release
 
 // Convert turns JetBrains releases into a Cuppa result set
-func (jbs Releases) Convert(name string) *results.ResultSet {
+func (jbs Releases) Convert(name, code string) *results.ResultSet {
 	rs := results.NewResultSet(name)
-	code := ReleaseCodes[name]
+	// Products are versioned by year, like "2023.3.3"
+	rs.SetScheme(version.CalVer)
 	for _, rel := range jbs[code] {
 		if r := rel.Convert(); r != nil {
 			r.Name = name
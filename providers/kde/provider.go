@@ -17,29 +17,28 @@
 package kde
 
 import (
-	"bytes"
-	"fmt"
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	"path"
 	"regexp"
 	"strings"
-	"time"
+	"sync"
+	"unicode"
 )
 
 const (
-	// ListingPrefix is the prefix of all paths in the KDE listing that is hidden by HTTP
-	ListingPrefix = "/srv/archives/ftp/"
-	// SourceFormat3 is the string format for KDE sources with 3 pieces
-	SourceFormat3 = "https://download.kde.org/%s/%s/%s-%s.tar.xz"
-	// SourceFormat4 is the string format for KDE sources with 4 pieces
-	SourceFormat4 = "https://download.kde.org/%s/%s/%s/%s-%s.tar.xz"
-	// SourceFormat5 is the string format for KDE sources with 5 pieces
-	SourceFormat5 = "https://download.kde.org/%s/%s/%s/%s/%s-%s.tar.xz"
-	// SourceFormat6 is the string format for KDE sources with 6 pieces
-	SourceFormat6 = "https://download.kde.org/%s/%s/%s/%s/%s/%s-%s.tar.xz"
+	// SourceBase is the root of all KDE sources
+	SourceBase = "https://download.kde.org/"
+	// MaxRequests is the largest number of concurrent requests to make to KDE
+	MaxRequests = 8
 )
 
-// TarballRegex matches KDE sources
-var TarballRegex = regexp.MustCompile("https?://.*download.kde.org/(.+)")
+var (
+	// TarballRegex matches KDE sources
+	TarballRegex = regexp.MustCompile("https?://.*download.kde.org/(.+)")
+	// FileRegex splits a KDE tarball into its name, version and extension
+	FileRegex = regexp.MustCompile("^(.+?)-(\\d[^-]*?)\\.(tar\\.[^.]+)$")
+)
 
 // Provider is the upstream provider interface for KDE
 type Provider struct{}
@@ -53,7 +52,7 @@ func (c Provider) String() string {
 func (c Provider) Match(query string) (params []string) {
 	if sm := TarballRegex.FindStringSubmatch(query); len(sm) > 1 {
 		pieces := strings.Split(sm[1], "/")
-		if len(pieces) > 2 || len(pieces) < 7 {
+		if len(pieces) > 1 && FileRegex.MatchString(pieces[len(pieces)-1]) {
 			params = append(params, sm[1])
 		}
 	}
@@ -62,7 +61,7 @@ func (c Provider) Match(query string) (params []string) {
 
 // Latest finds the newest release for a KDE package
 func (c Provider) Latest(params []string) (r *results.Result, err error) {
-	rs, err := c.Releases(params)
+	rs, err := c.find(params, true)
 	if err == nil {
 		r = rs.Last()
 	}
@@ -71,74 +70,123 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 
 // Releases finds all matching releases for a KDE package
 func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
-	name := params[0]
-	if len(listing) == 0 {
-		getListing()
-	}
-	buff := bytes.NewBuffer(listing)
-	pieces := strings.Split(name, "/")
-	pieces2 := strings.Split(pieces[len(pieces)-1], "-")
-	name = strings.Join(pieces2[0:len(pieces2)-1], "-")
-	var searchPrefix string
-	switch len(pieces) {
-	case 3:
-		searchPrefix = ListingPrefix + strings.Join(pieces[0:len(pieces)-1], "/") + ":\n"
-	case 4:
-		searchPrefix = ListingPrefix + strings.Join(pieces[0:len(pieces)-2], "/") + ":\n"
-	case 5, 6:
-		searchPrefix = ListingPrefix + strings.Join(pieces[0:len(pieces)-3], "/") + ":\n"
+	return c.find(params, false)
+}
+
+// find gets the releases of a KDE package, stopping at the newest one when "latest" is set
+func (c Provider) find(params []string, latest bool) (rs *results.ResultSet, err error) {
+	pieces := strings.Split(params[0], "/")
+	sm := FileRegex.FindStringSubmatch(pieces[len(pieces)-1])
+	if len(sm) != 4 {
+		err = results.NotFound
+		return
 	}
-	rs = results.NewResultSet(name)
-	for {
-		line, err := buff.ReadString('\n')
-		if err != nil {
+	name, version, ext := sm[1], sm[2], sm[3]
+	dirs := pieces[:len(pieces)-1]
+	// Find the directory named for this release, like "stable/plasma/5.27.8/" or "stable/frameworks/5.110/"
+	vi := -1
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if dirs[i] == version || strings.HasPrefix(version, dirs[i]+".") {
+			vi = i
 			break
 		}
-		if line != searchPrefix {
+	}
+	if vi < 0 {
+		rs, err = listFiles(name, ext, strings.Join(dirs, "/")+"/")
+	} else {
+		rs, err = listVersions(name, ext, dirs[:vi], dirs[vi+1:], strings.TrimPrefix(version, dirs[vi]), latest)
+	}
+	if err == nil && rs.Len() == 0 {
+		err = results.NotFound
+	}
+	return
+}
+
+// listFiles finds every release of a package in a single directory
+func listFiles(name, ext, dir string) (rs *results.ResultSet, err error) {
+	entries, err := util.ListHTTP(SourceBase + dir)
+	if err != nil {
+		return
+	}
+	rs = results.NewResultSet(name)
+	for _, entry := range entries {
+		sm := FileRegex.FindStringSubmatch(entry.Name)
+		if entry.Dir || len(sm) != 4 || sm[1] != name || sm[3] != ext {
 			continue
 		}
-		for line != "\n" {
-			line, err = buff.ReadString('\n')
-			if err != nil || line == "\n" {
+		rs.AddResult(results.NewResult(name, sm[2], SourceBase+dir+entry.Name, entry.Time))
+	}
+	return
+}
+
+// listVersions finds every release of a package from the release directories in "base", stopping at the
+// newest one when "latest" is set
+func listVersions(name, ext string, base, sub []string, tail string, latest bool) (rs *results.ResultSet, err error) {
+	var dir string
+	if len(base) > 0 {
+		dir = strings.Join(base, "/") + "/"
+	}
+	entries, err := util.ListHTTP(SourceBase + dir)
+	if err != nil {
+		return
+	}
+	all := results.NewResultSet(name)
+	for _, entry := range entries {
+		if !entry.Dir || !unicode.IsDigit(rune(entry.Name[0])) {
+			continue
+		}
+		version := entry.Name + tail
+		pieces := append([]string{entry.Name}, sub...)
+		location := SourceBase + dir + strings.Join(append(pieces, name+"-"+version+"."+ext), "/")
+		all.AddResult(results.NewResult(name, version, location, entry.Time))
+	}
+	// Packages can be dropped from or moved out of newer release directories
+	candidates := all.Newest(all.Len())
+	rs = results.NewResultSet(name)
+	if latest {
+		for _, r := range candidates {
+			if exists(r) {
+				rs.AddResult(r)
 				break
 			}
-			fields := strings.Fields(line)
-			fd := fields[len(fields)-1]
-			parts := strings.Split(fd, "-")
-			last := parts[len(parts)-1]
-			parts = strings.Split(last, ".")
-			var vRaw []string
-			for _, p := range parts {
-				if p[0] > 57 || p[0] < 48 {
-					break
-				}
-				vRaw = append(vRaw, p)
-			}
-			version := strings.Join(vRaw, ".")
-			if len(version) == 0 || version[0] > 57 || version[0] < 48 {
-				continue
-			}
-			updated, _ := time.Parse("2006-01-02 15:04", strings.Join(fields[len(fields)-3:len(fields)-2], " "))
-			var location string
-			switch len(pieces) {
-			case 3:
-				location = fmt.Sprintf(SourceFormat3, pieces[0], version, name, version)
-			case 4:
-				location = fmt.Sprintf(SourceFormat4, pieces[0], pieces[1], version, name, version)
-			case 5:
-				location = fmt.Sprintf(SourceFormat5, pieces[0], pieces[1], version, pieces[3], name, version)
-			case 6:
-				location = fmt.Sprintf(SourceFormat6, pieces[0], pieces[1], version, pieces[3], pieces[4], name, version)
-			}
-			r := results.NewResult(name, version, location, updated)
-			rs.AddResult(r)
 		}
-		break
-	}
-	if rs.Len() == 0 {
-		err = results.NotFound
 		return
 	}
-	err = nil
+	found := make([]bool, len(candidates))
+	limit := make(chan struct{}, MaxRequests)
+	var wg sync.WaitGroup
+	for i, r := range candidates {
+		wg.Add(1)
+		go func(i int, r *results.Result) {
+			defer wg.Done()
+			limit <- struct{}{}
+			found[i] = exists(r)
+			<-limit
+		}(i, r)
+	}
+	wg.Wait()
+	for i, r := range candidates {
+		if found[i] {
+			rs.AddResult(r)
+		}
+	}
 	return
 }
+
+// exists checks that the tarball of a result is listed in its directory, using its modification time
+func exists(r *results.Result) bool {
+	dir, file := path.Split(r.Location)
+	entries, err := util.ListHTTP(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.Dir && entry.Name == file {
+			if !entry.Time.IsZero() {
+				r.Published = entry.Time
+			}
+			return true
+		}
+	}
+	return false
+}
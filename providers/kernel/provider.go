@@ -0,0 +1,126 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package kernel
+
+import (
+	"bufio"
+	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// ReleasesAPI is the location of the kernel.org releases listing
+	ReleasesAPI = "https://www.kernel.org/releases.json"
+	// ChecksumsFile is the name of the signed checksum listing in each kernel.org directory
+	ChecksumsFile = "sha256sums.asc"
+)
+
+var (
+	// LinuxRegex matches Linux kernel sources
+	LinuxRegex = regexp.MustCompile("https?://(?:cdn|www|mirrors\\.edge)\\.kernel\\.org/pub/linux/kernel/v[^/]+/linux-(\\d+\\.\\d+)[^/]*\\.tar\\.[^/]+$")
+	// SoftwareRegex matches other sources hosted on kernel.org
+	SoftwareRegex = regexp.MustCompile("https?://(?:cdn|www|mirrors\\.edge)\\.kernel\\.org/pub/(.+/)([^/]+)-[^/-]+\\.tar\\.[^/]+$")
+	// TarballRegex matches the tarballs listed in the checksum file
+	TarballRegex = regexp.MustCompile("^(.+)-(\\d[^-]*)\\.tar\\.(?:xz|gz|bz2)$")
+)
+
+// Provider is the upstream provider interface for kernel.org
+type Provider struct{}
+
+// String gives the name of this provider
+func (c Provider) String() string {
+	return "Kernel.org"
+}
+
+// Match checks to see if this provider can handle this kind of query
+func (c Provider) Match(query string) (params []string) {
+	if strings.HasPrefix(query, "kernel|") {
+		params = append(params, "linux", strings.TrimPrefix(query, "kernel|"), "")
+		return
+	}
+	if sm := LinuxRegex.FindStringSubmatch(query); len(sm) > 1 {
+		params = append(params, "linux", "", sm[1])
+		return
+	}
+	if sm := SoftwareRegex.FindStringSubmatch(query); len(sm) > 2 {
+		params = append(params, sm[2], sm[1])
+	}
+	return
+}
+
+// Latest finds the newest release for a kernel.org package
+func (c Provider) Latest(params []string) (r *results.Result, err error) {
+	rs, err := c.Releases(params)
+	if err == nil {
+		r = rs.Last()
+	}
+	return
+}
+
+// Releases finds all matching releases for a kernel.org package
+func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
+	if params[0] == "linux" && len(params) == 3 {
+		return c.linux(params[1], params[2])
+	}
+	return c.software(params[0], params[1])
+}
+
+// linux gets the kernel releases for a moniker or series, an existing series is kept if it is longterm
+func (c Provider) linux(filter, series string) (rs *results.ResultSet, err error) {
+	var krs Releases
+	if err = util.FetchJSON(ReleasesAPI, "releases", &krs); err != nil {
+		return
+	}
+	if len(filter) == 0 && krs.IsLongterm(series) {
+		filter = series
+	}
+	rs = krs.Convert(filter)
+	if rs.Len() == 0 {
+		err = results.NotFound
+	}
+	return
+}
+
+// software gets the releases listed in the checksum file of a kernel.org directory
+func (c Provider) software(name, dir string) (rs *results.ResultSet, err error) {
+	path := "https://cdn.kernel.org/pub/" + dir
+	body, err := util.Fetch(path+ChecksumsFile, "checksums")
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	rs = results.NewResultSet(name)
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || len(fields[0]) != 64 {
+			continue
+		}
+		sm := TarballRegex.FindStringSubmatch(fields[1])
+		if len(sm) != 3 || sm[1] != name {
+			continue
+		}
+		rs.AddResult(results.NewResult(name, sm[2], path+fields[1], time.Time{}))
+	}
+	if rs.Len() == 0 {
+		err = results.NotFound
+	}
+	return
+}
@@ -0,0 +1,105 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package kernel
+
+import (
+	"github.com/DataDrake/cuppa/results"
+	"strings"
+	"time"
+)
+
+// Monikers that are considered for kernel releases, "linux-next" is never included
+var Monikers = []string{"mainline", "stable", "longterm"}
+
+// Release is a JSON representation of a single kernel.org release
+type Release struct {
+	EOL      bool   `json:"iseol"`
+	Version  string `json:"version"`
+	Moniker  string `json:"moniker"`
+	Source   string `json:"source"`
+	Released struct {
+		Timestamp int64 `json:"timestamp"`
+	} `json:"released"`
+}
+
+// Series gets the "major.minor" series of this release
+func (kr Release) Series() string {
+	pieces := strings.SplitN(kr.Version, ".", 3)
+	if len(pieces) < 2 {
+		return kr.Version
+	}
+	return strings.SplitN(pieces[0]+"."+pieces[1], "-", 2)[0]
+}
+
+// Convert turns a kernel.org release into a Cuppa result
+func (kr Release) Convert() *results.Result {
+	var published time.Time
+	if kr.Released.Timestamp > 0 {
+		published = time.Unix(kr.Released.Timestamp, 0).UTC()
+	}
+	return results.NewResult("linux", kr.Version, kr.Source, published)
+}
+
+// Releases is a JSON representation of the kernel.org "releases.json"
+type Releases struct {
+	Releases []Release `json:"releases"`
+}
+
+// IsLongterm checks if a series is currently maintained as a longterm release
+func (krs Releases) IsLongterm(series string) bool {
+	for _, kr := range krs.Releases {
+		if kr.Moniker == "longterm" && kr.Series() == series {
+			return true
+		}
+	}
+	return false
+}
+
+// Convert turns kernel.org releases into a Cuppa result set, limited by a moniker or series filter
+func (krs Releases) Convert(filter string) *results.ResultSet {
+	rs := results.NewResultSet("linux")
+	for _, kr := range krs.Releases {
+		if len(kr.Source) == 0 {
+			continue
+		}
+		switch {
+		case len(filter) == 0:
+			if !isMoniker(kr.Moniker) {
+				continue
+			}
+		case isMoniker(filter):
+			if kr.Moniker != filter {
+				continue
+			}
+		default:
+			if kr.Series() != filter {
+				continue
+			}
+		}
+		rs.AddResult(kr.Convert())
+	}
+	return rs
+}
+
+func isMoniker(moniker string) bool {
+	for _, m := range Monikers {
+		if m == moniker {
+			return true
+		}
+	}
+	return false
+}
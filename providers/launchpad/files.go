@@ -16,6 +16,16 @@
 
 package launchpad
 
+import (
+	"strings"
+)
+
+// TarballType is the Launchpad file type of release tarballs
+const TarballType = "Code Release Tarball"
+
+// TarballExts are the supported tarball extensions, in order of preference
+var TarballExts = []string{"tar.xz", "tar.bz2", "tar.gz"}
+
 // File is a JSON representation of a Launchpad File
 type File struct {
 	Link     string `json:"file_link"`
@@ -27,3 +37,20 @@ type File struct {
 type FileList struct {
 	Files []File `json:"entries"`
 }
+
+// Tarball finds the release tarball, preferring the extension "ext" when available
+func (fl FileList) Tarball(ext string) *File {
+	exts := TarballExts
+	if len(ext) > 0 {
+		exts = append([]string{ext}, exts...)
+	}
+	for _, e := range exts {
+		for i, f := range fl.Files {
+			// "file_link" is an API URL for the file, ending with "/file"
+			if f.Type == TarballType && strings.HasSuffix(strings.TrimSuffix(f.Link, "/file"), "."+e) {
+				return &fl.Files[i]
+			}
+		}
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/DataDrake/cuppa/results"
 	"github.com/DataDrake/cuppa/util"
 	"regexp"
+	"sync"
 )
 
 const (
@@ -31,11 +32,13 @@ const (
 	// SeriesAPI is the format string for the Launchpad Series API
 	SeriesAPI = "https://api.launchpad.net/1.0/%s/series"
 	// SourceFormat is the format string for Launchpad tarballs
-	SourceFormat = "https://launchpad.net/%s/%s/%s/+download/%s-%s.tar.gz"
+	SourceFormat = "https://launchpad.net/%s/%s/%s/+download/%s"
+	// MaxRequests is the largest number of concurrent requests to make to Launchpad
+	MaxRequests = 8
 )
 
 // SourceRegex matches Launchpad source tarballs
-var SourceRegex = regexp.MustCompile("https?://launchpad.net/(.*)/.*/.*/\\+download/.*.tar.gz")
+var SourceRegex = regexp.MustCompile("https?://launchpad.net/(.*)/.*/.*/\\+download/.*\\.(tar\\.(?:gz|xz|bz2))$")
 
 // Provider is the upstream provider interface for launchpad
 type Provider struct{}
@@ -47,7 +50,7 @@ func (c Provider) String() string {
 
 // Match checks to see if this provider can handle this kind of query
 func (c Provider) Match(query string) (params []string) {
-	if sm := SourceRegex.FindStringSubmatch(query); len(sm) > 1 {
+	if sm := SourceRegex.FindStringSubmatch(query); len(sm) > 2 {
 		params = sm[1:]
 	}
 	return
@@ -65,6 +68,10 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 // Releases finds all matching releases for a launchpad package
 func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 	name := params[0]
+	ext := ""
+	if len(params) > 1 {
+		ext = params[1]
+	}
 	// Query the API
 	url := fmt.Sprintf(SeriesAPI, name)
 	var seriesList SeriesList
@@ -73,6 +80,9 @@ func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 	}
 	// Proccess Releases
 	var lrs Releases
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, MaxRequests)
 	for _, s := range seriesList.Entries {
 		// Only Active Series
 		if !s.Active {
@@ -86,36 +96,67 @@ func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 		default:
 			continue
 		}
-		url := fmt.Sprintf(ReleasesAPI, name, s.Name)
-		var vl VersionList
-		if err = util.FetchJSON(url, "releases", &vl); err != nil {
-			continue
-		}
-		for i := len(vl.Versions) - 1; i >= 0; i-- {
-			r := vl.Versions[i]
-			url := fmt.Sprintf(FilesAPI, name, s.Name, r.Number)
-			var fl FileList
-			if err = util.FetchJSON(url, "files", &fl); err != nil {
-				continue
-			}
-			var lr Release
-			for _, f := range fl.Files {
-				if f.Type != "Code Release Tarball" {
-					continue
-				}
-				lr.name = name
-				lr.series = s.Name
-				lr.release = r.Number
-				lr.uploaded = f.Uploaded
-			}
-			lrs = append(lrs, lr)
-		}
+		wg.Add(1)
+		go func(series string) {
+			defer wg.Done()
+			found := fetchSeries(name, series, ext, limit)
+			lock.Lock()
+			lrs = append(lrs, found...)
+			lock.Unlock()
+		}(s.Name)
 	}
+	wg.Wait()
 	if len(lrs) == 0 {
 		err = results.NotFound
 		return
 	}
 	rs = lrs.Convert(name)
-	err = nil
+	return
+}
+
+// fetchSeries gets the releases of a single series, looking up their files concurrently while
+// sharing a limit on concurrent requests with every other series
+func fetchSeries(name, series, ext string, limit chan struct{}) (lrs Releases) {
+	url := fmt.Sprintf(ReleasesAPI, name, series)
+	var vl VersionList
+	limit <- struct{}{}
+	err := util.FetchJSON(url, "releases", &vl)
+	<-limit
+	if err != nil {
+		return
+	}
+	found := make([]*Release, len(vl.Versions))
+	var wg sync.WaitGroup
+	for i, v := range vl.Versions {
+		wg.Add(1)
+		go func(i int, number string) {
+			defer wg.Done()
+			url := fmt.Sprintf(FilesAPI, name, series, number)
+			var fl FileList
+			limit <- struct{}{}
+			err := util.FetchJSON(url, "files", &fl)
+			<-limit
+			if err != nil {
+				return
+			}
+			f := fl.Tarball(ext)
+			if f == nil {
+				return
+			}
+			found[i] = &Release{
+				name:     name,
+				series:   series,
+				release:  number,
+				link:     f.Link,
+				uploaded: f.Uploaded,
+			}
+		}(i, v.Number)
+	}
+	wg.Wait()
+	for _, lr := range found {
+		if lr != nil {
+			lrs = append(lrs, *lr)
+		}
+	}
 	return
 }
@@ -19,6 +19,8 @@ package launchpad
 import (
 	"fmt"
 	"github.com/DataDrake/cuppa/results"
+	"path"
+	"strings"
 	"time"
 )
 
@@ -27,13 +29,16 @@ type Release struct {
 	name     string
 	release  string
 	series   string
+	link     string
 	uploaded string
 }
 
 // Convert turns a Launchpad release into a Cuppa result
 func (lr *Release) Convert() *results.Result {
 	published, _ := time.Parse(time.RFC3339, lr.uploaded)
-	location := fmt.Sprintf(SourceFormat, lr.name, lr.series, lr.release, lr.name, lr.release)
+	// "file_link" redirects to the public download of the same file
+	file := path.Base(strings.TrimSuffix(lr.link, "/file"))
+	location := fmt.Sprintf(SourceFormat, lr.name, lr.series, lr.release, file)
 	return results.NewResult(lr.name, lr.release, location, published)
 }
 
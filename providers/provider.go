@@ -18,19 +18,24 @@ package providers
 
 import (
 	"github.com/DataDrake/cuppa/providers/cpan"
+	"github.com/DataDrake/cuppa/providers/fossil"
 	"github.com/DataDrake/cuppa/providers/git"
 	"github.com/DataDrake/cuppa/providers/github"
 	"github.com/DataDrake/cuppa/providers/gitlab"
 	"github.com/DataDrake/cuppa/providers/gnome"
 	"github.com/DataDrake/cuppa/providers/gnu"
 	"github.com/DataDrake/cuppa/providers/hackage"
+	"github.com/DataDrake/cuppa/providers/hg"
 	"github.com/DataDrake/cuppa/providers/html"
 	"github.com/DataDrake/cuppa/providers/jetbrains"
 	"github.com/DataDrake/cuppa/providers/kde"
+	"github.com/DataDrake/cuppa/providers/kernel"
 	"github.com/DataDrake/cuppa/providers/launchpad"
+	"github.com/DataDrake/cuppa/providers/pyindex"
 	"github.com/DataDrake/cuppa/providers/pypi"
 	"github.com/DataDrake/cuppa/providers/rubygems"
 	"github.com/DataDrake/cuppa/providers/sourceforge"
+	"github.com/DataDrake/cuppa/providers/svn"
 	"github.com/DataDrake/cuppa/results"
 )
 
@@ -54,10 +59,15 @@ func All() []Provider {
 		html.Provider{},
 		jetbrains.Provider{},
 		kde.Provider{},
+		kernel.Provider{},
 		launchpad.Provider{},
+		pyindex.Provider{},
 		pypi.Provider{},
 		rubygems.Provider{},
 		sourceforge.Provider{},
+		fossil.Provider{},
+		hg.Provider{},
+		svn.Provider{},
 		git.Provider{}, // Git should be last to avoid using it unless necessary
 	}
 }
@@ -0,0 +1,137 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pyindex
+
+import (
+	"encoding/json"
+	"github.com/DataDrake/cuppa/providers/pypi"
+	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// AnchorRegex matches the anchors of a PEP 503 project page
+	AnchorRegex = regexp.MustCompile("(?is)<a\\s([^>]*)>([^<]*)</a>")
+	// HrefRegex matches the link of an anchor
+	HrefRegex = regexp.MustCompile("(?i)href\\s*=\\s*[\"']([^\"']*)[\"']")
+	// YankedRegex matches the PEP 592 yanked attribute of an anchor
+	YankedRegex = regexp.MustCompile("(?i)data-yanked")
+)
+
+// File is a JSON representation of a file on a PEP 691 project page
+type File struct {
+	Filename   string            `json:"filename"`
+	URL        string            `json:"url"`
+	Hashes     map[string]string `json:"hashes"`
+	Yanked     interface{}       `json:"yanked"`
+	UploadTime string            `json:"upload-time"`
+}
+
+// IsYanked checks if a file was yanked, "yanked" is either a bool or the reason for yanking
+func (f File) IsYanked() bool {
+	switch y := f.Yanked.(type) {
+	case bool:
+		return y
+	case string:
+		return true
+	}
+	return false
+}
+
+// Page is a JSON representation of a PEP 691 project page
+type Page struct {
+	Files []File `json:"files"`
+}
+
+// ParseJSON reads the files from a PEP 691 project page
+func ParseJSON(in io.Reader) (files []File, err error) {
+	var page Page
+	if err = json.NewDecoder(in).Decode(&page); err == nil {
+		files = page.Files
+	}
+	return
+}
+
+// ParseHTML reads the files from a PEP 503 project page
+func ParseHTML(in io.Reader) (files []File, err error) {
+	raw, err := ioutil.ReadAll(in)
+	if err != nil {
+		return
+	}
+	for _, sm := range AnchorRegex.FindAllStringSubmatch(string(raw), -1) {
+		hm := HrefRegex.FindStringSubmatch(sm[1])
+		if len(hm) < 2 {
+			continue
+		}
+		f := File{
+			Filename: strings.TrimSpace(html.UnescapeString(sm[2])),
+			URL:      html.UnescapeString(hm[1]),
+			Hashes:   make(map[string]string),
+		}
+		// Hashes are provided as a URL fragment of "<algorithm>=<digest>"
+		if i := strings.Index(f.URL, "#"); i >= 0 {
+			if hash := strings.SplitN(f.URL[i+1:], "=", 2); len(hash) == 2 {
+				f.Hashes[hash[0]] = hash[1]
+			}
+			f.URL = f.URL[:i]
+		}
+		if YankedRegex.MatchString(sm[1]) {
+			f.Yanked = true
+		}
+		files = append(files, f)
+	}
+	return
+}
+
+// Files is a collection of files from a project page
+type Files []File
+
+// Convert turns the source distributions of a project into a Cuppa result set
+func (fs Files) Convert(name string, base *url.URL) *results.ResultSet {
+	rs := results.NewResultSet(name)
+	rs.SetScheme(version.PEP440)
+	found := make(map[string]bool)
+	// Prefer tarballs over zip files for the same version
+	for _, zips := range []bool{false, true} {
+		for _, f := range fs {
+			if f.IsYanked() || strings.HasSuffix(f.Filename, ".zip") != zips {
+				continue
+			}
+			sm := pypi.SdistRegex.FindStringSubmatch(f.Filename)
+			if len(sm) < 3 || pypi.Normalize(sm[1]) != name || found[sm[2]] {
+				continue
+			}
+			found[sm[2]] = true
+			location := f.URL
+			if u, err := url.Parse(f.URL); err == nil && base != nil {
+				location = base.ResolveReference(u).String()
+			}
+			published, _ := time.Parse(time.RFC3339, f.UploadTime)
+			r := results.NewResult(name, sm[2], location, published)
+			r.AddExtra("SHA256", f.Hashes["sha256"])
+			rs.AddResult(r)
+		}
+	}
+	return rs
+}
@@ -0,0 +1,137 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pyindex
+
+import (
+	"github.com/DataDrake/cuppa/config"
+	"github.com/DataDrake/cuppa/providers/pypi"
+	"github.com/DataDrake/cuppa/results"
+	log "github.com/DataDrake/waterlog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// AcceptHeader asks for PEP 691 JSON, falling back to PEP 503 HTML
+	AcceptHeader = "application/vnd.pypi.simple.v1+json, application/vnd.pypi.simple.v1+html;q=0.2, text/html;q=0.1"
+	// JSONType is the PEP 691 JSON content type
+	JSONType = "application/vnd.pypi.simple.v1+json"
+)
+
+// client supports "file://" indexes in addition to HTTP(S), for static mirrors on disk
+var client = &http.Client{Transport: newTransport()}
+
+func newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+	return t
+}
+
+// Provider is the upstream provider interface for PEP 503 and PEP 691 simple indexes
+type Provider struct{}
+
+// String gives the name of this provider
+func (c Provider) String() string {
+	return "PyIndex"
+}
+
+// Match checks to see if this provider can handle this kind of query
+func (c Provider) Match(query string) (params []string) {
+	// Explicit index, as "pyindex|<index URL>|<project>"
+	if strings.HasPrefix(query, "pyindex|") {
+		pieces := strings.Split(query, "|")
+		if len(pieces) == 3 {
+			params = append(params, pieces[1], pypi.Normalize(pieces[2]))
+		}
+		return
+	}
+	// Files hosted on the same server as a configured index
+	u, err := url.Parse(query)
+	if err != nil {
+		return
+	}
+	sm := pypi.SdistRegex.FindStringSubmatch(path.Base(u.Path))
+	if len(sm) < 2 {
+		return
+	}
+	for _, index := range config.Global.PyIndex.Indexes {
+		if iu, err := url.Parse(index); err == nil && iu.Scheme == u.Scheme && iu.Host == u.Host {
+			params = append(params, index, pypi.Normalize(sm[1]))
+			return
+		}
+	}
+	return
+}
+
+// Latest finds the newest release for a simple index package
+func (c Provider) Latest(params []string) (r *results.Result, err error) {
+	rs, err := c.Releases(params)
+	if err == nil {
+		r = rs.Last()
+	}
+	return
+}
+
+// Releases finds all matching releases for a simple index package
+func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
+	index, name := params[0], params[1]
+	page := strings.TrimSuffix(index, "/") + "/" + name + "/"
+	req, err := http.NewRequest("GET", page, nil)
+	if err != nil {
+		log.Debugf("Failed to build request: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	req.Header.Set("Accept", AcceptHeader)
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Debugf("Failed to get releases: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	defer resp.Body.Close()
+	// Translate Status Code
+	switch resp.StatusCode {
+	case 200:
+		break
+	case 404:
+		err = results.NotFound
+		return
+	default:
+		err = results.Unavailable
+		return
+	}
+	// Decode response
+	var files []File
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), JSONType) {
+		files, err = ParseJSON(resp.Body)
+	} else {
+		files, err = ParseHTML(resp.Body)
+	}
+	if err != nil {
+		log.Debugf("Failed to decode response: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	base, _ := url.Parse(page)
+	if rs = Files(files).Convert(name, base); rs.Len() == 0 {
+		err = results.NotFound
+	}
+	return
+}
@@ -0,0 +1,56 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pyindex
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchExplicit(t *testing.T) {
+	params := Provider{}.Match("pyindex|https://example.com/simple/|Foo_Bar")
+	if len(params) != 2 {
+		t.Fatalf("Expected 2 params, found '%d'", len(params))
+	}
+	if params[1] != "foo-bar" {
+		t.Errorf("Expected normalized name 'foo-bar', found '%s'", params[1])
+	}
+}
+
+func TestReleasesStatic(t *testing.T) {
+	dir, err := filepath.Abs("testdata/simple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := Provider{}.Releases([]string{"file://" + dir + "/", "foo-bar"})
+	if err != nil {
+		t.Fatalf("Failed to get releases: %s", err)
+	}
+	if rs.Len() != 2 {
+		t.Errorf("Expected 2 releases, found '%d'", rs.Len())
+	}
+	r := rs.Last()
+	if v := r.Version.String(); v != "1.1" {
+		t.Errorf("Expected version '1.1', found '%s'", v)
+	}
+	if loc := "file://" + filepath.Join(filepath.Dir(dir), "packages", "foo-bar-1.1.tar.gz"); r.Location != loc {
+		t.Errorf("Expected location '%s', found '%s'", loc, r.Location)
+	}
+	if sum := r.Extra["SHA256"]; sum != "cccc" {
+		t.Errorf("Expected checksum 'cccc', found '%s'", sum)
+	}
+}
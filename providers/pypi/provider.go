@@ -31,8 +31,21 @@ const (
 	DateFormat = "2006-01-02T15:04:05"
 )
 
-// TarballRegex matches PyPi source tarballs
-var TarballRegex = regexp.MustCompile("https?://[^/]*py[^/]*/packages/(?:[^/]+/)+(.+)$")
+var (
+	// TarballRegex matches PyPi source tarballs
+	TarballRegex = regexp.MustCompile("https?://[^/]*py[^/]*/packages/(?:[^/]+/)+(.+)$")
+	// SdistRegex splits a source distribution filename into its name and version
+	SdistRegex = regexp.MustCompile("^(.+?)-(\\d[^-]*)\\.(?:tar\\.gz|tar\\.bz2|tar\\.xz|tgz|zip)$")
+	// WheelRegex matches the name of a wheel filename
+	WheelRegex = regexp.MustCompile("^([^-]+)-[^-]+-.+\\.whl$")
+	// SeparatorRegex matches the runs of separators which are equivalent under PEP 503
+	SeparatorRegex = regexp.MustCompile("[-_.]+")
+)
+
+// Normalize converts a project name to its PEP 503 normalized form
+func Normalize(name string) string {
+	return strings.ToLower(SeparatorRegex.ReplaceAllString(name, "-"))
+}
 
 // Provider is the upstream provider interface for pypi
 type Provider struct{}
@@ -44,13 +57,16 @@ func (c Provider) String() string {
 
 // Match checks to see if this provider can handle this kind of query
 func (c Provider) Match(query string) (params []string) {
-	if sm := TarballRegex.FindStringSubmatch(query); len(sm) > 1 {
-		pieces := strings.Split(sm[1], "-")
-		if len(pieces) > 2 {
-			params = append(params, strings.Join(pieces[0:len(pieces)-1], "-"))
-			return
-		}
-		params = append(params, pieces[0])
+	sm := TarballRegex.FindStringSubmatch(query)
+	if len(sm) < 2 {
+		return
+	}
+	if fm := WheelRegex.FindStringSubmatch(sm[1]); len(fm) > 1 {
+		params = append(params, Normalize(fm[1]))
+		return
+	}
+	if fm := SdistRegex.FindStringSubmatch(sm[1]); len(fm) > 1 {
+		params = append(params, Normalize(fm[1]))
 	}
 	return
 }
@@ -60,8 +76,15 @@ func (c Provider) Latest(params []string) (r *results.Result, err error) {
 	name := params[0]
 	url := fmt.Sprintf(SourceAPI, name)
 	var cr LatestSource
-	if err = util.FetchJSON(url, "latest", &cr); err == nil {
-		r = cr.Convert(name)
+	if err = util.FetchJSON(url, "latest", &cr); err != nil {
+		return
+	}
+	// Fall back to older releases if every file of the latest was yanked
+	if r = cr.Convert(name); r == nil {
+		var rs *results.ResultSet
+		if rs, err = c.Releases(params); err == nil {
+			r = rs.Last()
+		}
 	}
 	return
 }
@@ -74,10 +97,8 @@ func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 	if err = util.FetchJSON(url, "releases", &crs); err != nil {
 		return
 	}
-	if len(crs.Releases) == 0 {
+	if rs = crs.Convert(name); rs.Len() == 0 {
 		err = results.NotFound
-		return
 	}
-	rs = crs.Convert(name)
 	return
 }
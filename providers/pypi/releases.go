@@ -18,14 +18,31 @@ package pypi
 
 import (
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
 	"time"
 )
 
-// ConvertURLS translates PyPi URLs to Cuppa results
+// ConvertURLS translates PyPi URLs to Cuppa results, preferring source distributions and skipping yanked files
 func ConvertURLS(cr []URL, name, version string) *results.Result {
-	u := cr[len(cr)-1]
-	published, _ := time.Parse(DateFormat, u.UploadTime)
-	return results.NewResult(name, version, u.URL, published)
+	var u *URL
+	for i := range cr {
+		if cr[i].Yanked {
+			continue
+		}
+		if u == nil || (cr[i].PackageType == "sdist" && u.PackageType != "sdist") {
+			u = &cr[i]
+		}
+	}
+	if u == nil {
+		return nil
+	}
+	published, err := time.Parse(time.RFC3339, u.UploadTimeISO)
+	if err != nil {
+		published, _ = time.Parse(DateFormat, u.UploadTime)
+	}
+	r := results.NewResult(name, version, u.URL, published)
+	r.AddExtra("SHA256", u.Digests.SHA256)
+	return r
 }
 
 // Releases holds one or more Source URLs
@@ -36,6 +53,7 @@ type Releases struct {
 // Convert turns PyPi releases into a Cuppa results set
 func (crs *Releases) Convert(name string) *results.ResultSet {
 	rs := results.NewResultSet(name)
+	rs.SetScheme(version.PEP440)
 	for ver, rel := range crs.Releases {
 		if r := ConvertURLS(rel, name, ver); r != nil {
 			rs.AddResult(r)
@@ -18,7 +18,6 @@ package pypi
 
 import (
 	"github.com/DataDrake/cuppa/results"
-	"time"
 )
 
 // Info contains a PyPi Version number
@@ -28,8 +27,14 @@ type Info struct {
 
 // URL contains a JSON representation of a PyPi tarball URL
 type URL struct {
-	UploadTime string `json:"upload_time"`
-	URL        string `json:"url"`
+	Digests struct {
+		SHA256 string `json:"sha256"`
+	} `json:"digests"`
+	PackageType   string `json:"packagetype"`
+	UploadTime    string `json:"upload_time"`
+	UploadTimeISO string `json:"upload_time_iso_8601"`
+	URL           string `json:"url"`
+	Yanked        bool   `json:"yanked"`
 }
 
 // LatestSource contains a JSON representation of a PyPi Source
@@ -40,7 +45,5 @@ type LatestSource struct {
 
 // Convert turns a PyPi latest into a Cuppa Result
 func (cr *LatestSource) Convert(name string) *results.Result {
-	u := cr.URLs[len(cr.URLs)-1]
-	published, _ := time.Parse(DateFormat, u.UploadTime)
-	return results.NewResult(name, cr.Info.Version, u.URL, published)
+	return ConvertURLS(cr.URLs, name, cr.Info.Version)
 }
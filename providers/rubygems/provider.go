@@ -21,21 +21,24 @@ import (
 	"github.com/DataDrake/cuppa/results"
 	"github.com/DataDrake/cuppa/util"
 	"regexp"
-	"strings"
 	"time"
 )
 
 const (
-	// LatestAPI is the string format for the Rubygems latest API
-	LatestAPI = "https://rubygems.org/api/v1/versions/%s/latest.json"
 	// VersionsAPI is the string format for the Rubygems versions API
 	VersionsAPI = "https://rubygems.org/api/v1/versions/%s.json"
 	// SourceFormat is the string format for Gem sources
 	SourceFormat = "https://rubygems.org/downloads/%s-%s.gem"
+	// RateLimit is the minimum time between requests to the Rubygems API
+	RateLimit = 100 * time.Millisecond
 )
 
-// GemRegex matches Rubygems sources
-var GemRegex = regexp.MustCompile("https?://rubygems.org/downloads/(.+).gem")
+func init() {
+	util.SetRateLimit("rubygems.org", RateLimit)
+}
+
+// GemRegex matches Rubygems sources, including platform gems like "name-1.0-x86_64-linux.gem"
+var GemRegex = regexp.MustCompile("https?://rubygems.org/downloads/(.+?)-\\d[^/]*\\.gem$")
 
 // Provider is the upstream provider interface for rubygems
 type Provider struct{}
@@ -48,25 +51,17 @@ func (c Provider) String() string {
 // Match checks to see if this provider can handle this kind of query
 func (c Provider) Match(query string) (params []string) {
 	if sm := GemRegex.FindStringSubmatch(query); len(sm) > 1 {
-		pieces := strings.Split(sm[1], "-")
-		if len(pieces) > 2 {
-			params = append(params, strings.Join(pieces[0:len(pieces)-1], "-"))
-			return
-		}
-		params = pieces[:1]
+		params = sm[1:]
 	}
 	return
 }
 
 // Latest finds the newest release for a rubygems package
 func (c Provider) Latest(params []string) (r *results.Result, err error) {
-	name := params[0]
-	url := fmt.Sprintf(LatestAPI, name)
-	var cr LatestVersion
-	if err = util.FetchJSON(url, "latest", &cr); err == nil {
-		r = cr.Convert(name)
+	rs, err := c.Releases(params)
+	if err == nil {
+		r = rs.Last()
 	}
-	time.Sleep(time.Second)
 	return
 }
 
@@ -78,10 +73,8 @@ func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
 	if err = util.FetchJSON(url, "releases", &crs); err != nil {
 		return
 	}
-	if len(crs) == 0 {
+	if rs = crs.Convert(name); rs.Len() == 0 {
 		err = results.NotFound
-		return
 	}
-	rs = crs.Convert(name)
 	return
 }
@@ -19,22 +19,33 @@ package rubygems
 import (
 	"fmt"
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
 	"time"
 )
 
+// SourcePlatform is the platform of pure Ruby gems, as opposed to Java or native variants
+const SourcePlatform = "ruby"
+
 // Version is a JSON representation of a version of a Gem
 type Version struct {
 	CreatedAt  string `json:"created_at"`
+	Platform   string `json:"platform"`
 	PreRelease bool   `json:"prerelease"`
 	Number     string `json:"number"`
+	SHA        string `json:"sha"`
 }
 
 // Convert turns a Rubygems version to a Cuppa result
 func (cr *Version) Convert(name string) *results.Result {
-	if cr.PreRelease {
+	if cr.Platform != SourcePlatform {
 		return nil
 	}
 	published, _ := time.Parse(time.RFC3339, cr.CreatedAt)
 	location := fmt.Sprintf(SourceFormat, name, cr.Number)
-	return results.NewResult(name, cr.Number, location, published)
+	r := results.NewResult(name, cr.Number, location, published)
+	r.AddExtra("SHA256", cr.SHA)
+	if cr.PreRelease {
+		r.MarkUnstable(version.RC)
+	}
+	return r
 }
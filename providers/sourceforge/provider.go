@@ -20,15 +20,30 @@ import (
 	"encoding/xml"
 	"fmt"
 	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/util"
 	log "github.com/DataDrake/waterlog"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
 )
 
 const (
 	// API is the format string for a sourceforge RSS feed
-	API = "https://sourceforge.net/projects/%s/rss?path=/%s"
+	API = "https://sourceforge.net/projects/%s/rss?path=/%s&limit=%d&offset=%d"
+	// DownloadFormat is the format string for a link to the SourceForge download redirector
+	DownloadFormat = "https://downloads.sourceforge.net/project/%s/%s"
+	// PageSize is the number of items requested for each page of a feed
+	PageSize = 100
+	// MaxPages is the largest number of pages to read from a single feed
+	MaxPages = 10
+	// MaxRedirects is the largest number of redirects to follow when resolving a mirror
+	MaxRedirects = 5
+	// MaxRequests is the largest number of concurrent requests to make to SourceForge
+	MaxRequests = 8
 )
 
 var (
@@ -36,6 +51,8 @@ var (
 	TarballRegex = regexp.MustCompile("https?://.*sourceforge.net/projects?/(.+)/files/(.+/)?(.+?)[\\-_]([\\d]+(?:.\\d+)*\\w*?)\\.(?:zip|tar\\..+z.*)(?:\\/download)?$")
 	// ProjectRegex matches SourceForge sources
 	ProjectRegex = regexp.MustCompile("https?://.*sourceforge.net/projects?/(.+)/(?:files/)?(.+?/)?(.+?)[\\-_]([\\d]+(?:.\\d+)*\\w*?).+$")
+	// FileRegex matches the project and path of a file from a "/download" link
+	FileRegex = regexp.MustCompile("https?://.*sourceforge.net/projects?/([^/]+)/files/(.+?)(?:/download)?$")
 )
 
 // Item represents an entry in the RSS Feed
@@ -51,17 +68,70 @@ type Feed struct {
 	Items   []Item   `xml:"channel>item"`
 }
 
-// toResults converts a Feed to a ResultSet
-func (f *Feed) toResults(name string) *results.ResultSet {
-	rs := results.NewResultSet(name)
+// toResults converts a Feed to a ResultSet, keeping only the files for a single package
+func (f *Feed) toResults(rs *results.ResultSet, name string) {
 	for _, item := range f.Items {
-		if sm := TarballRegex.FindStringSubmatch(item.Link); len(sm) > 4 {
-			pub, _ := time.Parse(time.RFC1123, item.Date+"C")
-			r := results.NewResult(name, sm[4], item.Link, pub)
-			rs.AddResult(r)
+		sm := TarballRegex.FindStringSubmatch(item.Link)
+		if len(sm) < 5 || sm[3] != name {
+			continue
 		}
+		pub, _ := time.Parse(time.RFC1123, item.Date+"C")
+		r := results.NewResult(name, sm[4], DownloadURL(item.Link), pub)
+		rs.AddResult(r)
 	}
-	return rs
+}
+
+// DownloadURL rewrites a "/download" link to use the SourceForge download redirector
+func DownloadURL(link string) string {
+	if sm := FileRegex.FindStringSubmatch(link); len(sm) > 2 {
+		return fmt.Sprintf(DownloadFormat, sm[1], sm[2])
+	}
+	return link
+}
+
+// Mirror resolves a download redirector link to a direct mirror link by reading its redirects one at a
+// time, falling back to the redirector link when no mirror is found
+func Mirror(link string) string {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	next := link
+	for i := 0; i < MaxRedirects; i++ {
+		resp, err := client.Head(next)
+		if err != nil {
+			log.Debugf("Failed to resolve mirror: %s\n", err)
+			break
+		}
+		resp.Body.Close()
+		loc, err := resp.Location()
+		if err != nil {
+			break
+		}
+		if !strings.HasSuffix(loc.Hostname(), "sourceforge.net") {
+			return loc.String()
+		}
+		next = loc.String()
+	}
+	return link
+}
+
+// Scope removes the release directories from a path, like "libmtp/1.1.17/" to "libmtp/"
+func Scope(path, version string) string {
+	pieces := strings.Split(strings.Trim(path, "/"), "/")
+	for len(pieces) > 0 {
+		last := pieces[len(pieces)-1]
+		if last == "" || unicode.IsDigit(rune(last[0])) || strings.Contains(last, version) {
+			pieces = pieces[:len(pieces)-1]
+			continue
+		}
+		break
+	}
+	if len(pieces) == 0 {
+		return ""
+	}
+	return strings.Join(pieces, "/") + "/"
 }
 
 // Provider is the upstream provider interface for SourceForge
@@ -86,51 +156,91 @@ func (c Provider) Match(query string) (params []string) {
 
 // Latest finds the newest release for a SourceForge package
 func (c Provider) Latest(params []string) (r *results.Result, err error) {
-	rs, err := c.Releases(params)
+	rs, err := c.find(params)
 	if err == nil {
-		r = rs.First()
+		r = rs.Last()
+		r.Location = Mirror(r.Location)
 	}
 	return
 }
 
 // Releases finds all matching releases for a SourceForge package
 func (c Provider) Releases(params []string) (rs *results.ResultSet, err error) {
+	if rs, err = c.find(params); err != nil {
+		return
+	}
+	limit := make(chan struct{}, MaxRequests)
+	var wg sync.WaitGroup
+	for _, r := range rs.Newest(rs.Len()) {
+		wg.Add(1)
+		go func(r *results.Result) {
+			defer wg.Done()
+			limit <- struct{}{}
+			r.Location = Mirror(r.Location)
+			<-limit
+		}(r)
+	}
+	wg.Wait()
+	return
+}
+
+// find gets all matching releases for a SourceForge package, located at the download redirector
+func (c Provider) find(params []string) (rs *results.ResultSet, err error) {
 	name := params[0]
 	sm := TarballRegex.FindStringSubmatch(name)
 	if len(sm) != 5 {
-		sm = ProjectRegex.FindStringSubmatch(name)
-		sm[1], sm[3] = sm[3], sm[1]
+		if sm = ProjectRegex.FindStringSubmatch(name); len(sm) != 5 {
+			err = results.NotFound
+			return
+		}
+		// Project links keep the release path with the project, like "libmtp/libmtp/1.1.17"
+		pieces := strings.SplitN(sm[1], "/", 2)
+		sm[1] = pieces[0]
+		if len(pieces) > 1 {
+			sm[2] = pieces[1] + "/" + sm[2]
+		}
 	}
-	// Query the API
-	resp, err := http.Get(fmt.Sprintf(API, sm[1], ""))
-	if err != nil {
-		log.Debugf("Failed to get releases: %s\n", err)
-		err = results.Unavailable
-		return
+	project, path, pkg := sm[1], Scope(sm[2], sm[4]), sm[3]
+	rs = results.NewResultSet(pkg)
+	seen := make(map[string]bool)
+	for page := 0; page < MaxPages; page++ {
+		// Query the API
+		var feed Feed
+		if err = fetchFeed(fmt.Sprintf(API, project, url.QueryEscape(path), PageSize, page*PageSize), &feed); err != nil {
+			if page == 0 {
+				return
+			}
+			break
+		}
+		// Stop when the feed runs out, or starts repeating itself
+		if len(feed.Items) == 0 || seen[feed.Items[0].Link] {
+			break
+		}
+		seen[feed.Items[0].Link] = true
+		feed.toResults(rs, pkg)
+		if len(feed.Items) < PageSize {
+			break
+		}
 	}
-	defer resp.Body.Close()
-	// Translate Status Code
-	switch resp.StatusCode {
-	case 200:
-		break
-	case 404:
+	err = nil
+	if rs.Len() == 0 {
 		err = results.NotFound
-		return
-	default:
-		err = results.Unavailable
-		return
 	}
+	return
+}
+
+// fetchFeed gets and decodes a single page of an RSS feed
+func fetchFeed(url string, feed *Feed) error {
+	body, err := util.Fetch(url, "releases")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
 	// decode response
-	dec := xml.NewDecoder(resp.Body)
-	var feed Feed
-	if err = dec.Decode(&feed); err != nil {
+	dec := xml.NewDecoder(body)
+	if err = dec.Decode(feed); err != nil {
 		log.Debugf("Failed to decode releases: %s\n", err)
-		err = results.Unavailable
-		return
+		return results.Unavailable
 	}
-	rs = feed.toResults(sm[3])
-	if rs.Len() == 0 {
-		err = results.NotFound
-	}
-	return
+	return nil
 }
@@ -0,0 +1,114 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package svn
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/DataDrake/cuppa/results"
+	"github.com/DataDrake/cuppa/version"
+	log "github.com/DataDrake/waterlog"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TagsRegex matches a Subversion tags directory or a single tag within it
+var TagsRegex = regexp.MustCompile("^((?:svn|https?)://.+)/tags(?:/.*)?$")
+
+// Entry is an XML representation of an entry from "svn ls --xml"
+type Entry struct {
+	Kind   string `xml:"kind,attr"`
+	Name   string `xml:"name"`
+	Commit struct {
+		Date string `xml:"date"`
+	} `xml:"commit"`
+}
+
+// Listing is an XML representation of the output of "svn ls --xml"
+type Listing struct {
+	Entries []Entry `xml:"list>entry"`
+}
+
+// Provider provides a common interface for each of the backend providers
+type Provider struct{}
+
+// String returns the name of this provider
+func (p Provider) String() string {
+	return "Subversion"
+}
+
+// Match checks to see if this provider can handle this kind of query
+func (p Provider) Match(query string) (params []string) {
+	if !strings.HasPrefix(query, "svn|") && !strings.HasPrefix(query, "svn://") {
+		return
+	}
+	query = strings.TrimPrefix(query, "svn|")
+	if sm := TagsRegex.FindStringSubmatch(query); len(sm) > 1 {
+		params = append(params, sm[1])
+		return
+	}
+	params = append(params, strings.TrimSuffix(query, "/"))
+	return
+}
+
+// Latest finds the newest release for a Subversion package
+func (p Provider) Latest(params []string) (r *results.Result, err error) {
+	rs, err := p.Releases(params)
+	if err == nil {
+		r = rs.Last()
+	}
+	return
+}
+
+// Releases finds all matching releases for a Subversion package
+func (p Provider) Releases(params []string) (rs *results.ResultSet, err error) {
+	name := params[0]
+	tags := name + "/tags"
+	// List the tags directory, no working copy is needed
+	var buff bytes.Buffer
+	cmd := exec.Command("svn", "ls", "--xml", "--non-interactive", tags)
+	cmd.Stdout = &buff
+	if err = cmd.Run(); err != nil {
+		log.Debugf("Failed to list tags: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	var list Listing
+	if err = xml.Unmarshal(buff.Bytes(), &list); err != nil {
+		log.Debugf("Failed to decode tags: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	// Convert tags to releases
+	repoName := path.Base(name)
+	rs = results.NewResultSet(name)
+	rs.SetNormalizer(version.TagRules)
+	for _, entry := range list.Entries {
+		if entry.Kind != "dir" {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339Nano, entry.Commit.Date)
+		rs.AddResult(results.NewResult(repoName, entry.Name, "svn|"+tags+"/"+entry.Name, date))
+	}
+	if rs.Len() == 0 {
+		err = results.NotFound
+	}
+	return
+}
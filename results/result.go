@@ -20,25 +20,73 @@ import (
 	"fmt"
 	"github.com/DataDrake/cuppa/version"
 	"os"
+	"sort"
 	"text/tabwriter"
 	"time"
 )
 
+// Channel is the least stable kind of release to accept in results
+var Channel = version.Stable
+
 // Result contains the information for a single query result
 type Result struct {
 	Name      string
 	Version   version.Version
+	Raw       string
+	Stability version.Stability
 	Location  string
 	Published time.Time
+	Extra     map[string]string
 }
 
 // NewResult creates a result with the specified values
 func NewResult(name, v string, location string, published time.Time) *Result {
-	r := &Result{name, version.NewVersion(v), location, published}
-	if r.Published.IsZero() {
-		r.Published = r.Version.FindDate()
+	return &Result{
+		Name:      name,
+		Version:   version.NewVersion(v),
+		Raw:       v,
+		Stability: version.Classify(v),
+		Location:  location,
+		Published: published,
+	}
+}
+
+// Normalize re-parses the version of a result after applying normalization rules, keeping its
+// stability if the original version was less stable, like "1.2.3-rc" with a rule to drop suffixes
+func (r *Result) Normalize(n version.Normalizer) {
+	raw := n.Normalize(r.Raw)
+	if raw == r.Raw {
+		return
+	}
+	r.Raw = raw
+	r.Version = version.NewVersion(raw)
+	if s := version.Classify(raw); s < r.Stability {
+		r.Stability = s
 	}
-	return r
+}
+
+// MarkUnstable records a provider's own knowledge that a release is unstable, like a GitHub pre-release,
+// unless its version already tells how unstable it is
+func (r *Result) MarkUnstable(s version.Stability) {
+	if r.Stability == version.Stable {
+		r.Stability = s
+	}
+}
+
+// Accepted checks if a result is at least as stable as the current Channel
+func (r *Result) Accepted() bool {
+	return r.Stability >= Channel
+}
+
+// AddExtra records additional provider-specific details, like checksums
+func (r *Result) AddExtra(key, value string) {
+	if len(value) == 0 {
+		return
+	}
+	if r.Extra == nil {
+		r.Extra = make(map[string]string)
+	}
+	r.Extra[key] = value
 }
 
 // Print pretty-prints a single Result
@@ -52,6 +100,17 @@ func (r *Result) Print() {
 	if !r.Published.IsZero() {
 		fmt.Fprintf(tw, "%s\t: %s\n", "Published", r.Published.Format(time.RFC3339))
 	}
+	if r.Stability != version.Stable {
+		fmt.Fprintf(tw, "%s\t: %s\n", "Stability", r.Stability)
+	}
+	keys := make([]string, 0, len(r.Extra))
+	for key := range r.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%s\t: %s\n", key, r.Extra[key])
+	}
 	tw.Flush()
 	fmt.Println()
 }
@@ -18,46 +18,90 @@ package results
 
 import (
 	"fmt"
+	"github.com/DataDrake/cuppa/config"
+	"github.com/DataDrake/cuppa/version"
+	log "github.com/DataDrake/waterlog"
 	"sort"
 )
 
 // ResultSet is a collection of the Results of a Provider query
 type ResultSet struct {
-	results []*Result
-	query   string
+	results         []*Result
+	query           string
+	scheme          version.Scheme
+	fixedScheme     bool
+	normalizer      version.Normalizer
+	fixedNormalizer bool
 }
 
 // NewResultSet creates as empty ResultSet for the provided query
 func NewResultSet(query string) *ResultSet {
-	return &ResultSet{make([]*Result, 0), query}
+	rs := &ResultSet{
+		results: make([]*Result, 0),
+		query:   query,
+		scheme:  version.Generic,
+	}
+	if name, ok := config.Global.Schemes[query]; ok {
+		if s := version.FindScheme(name); s != nil {
+			rs.scheme, rs.fixedScheme = s, true
+		} else {
+			log.Warnf("Unknown version scheme for %s: %s\n", query, name)
+		}
+	}
+	if names, ok := config.Global.Normalize[query]; ok {
+		if n, err := version.ParseRules(names); err == nil {
+			rs.normalizer, rs.fixedNormalizer = n, true
+		} else {
+			log.Warnf("Invalid normalization for %s: %s\n", query, err)
+		}
+	}
+	return rs
 }
 
-var skipWords = []string{
-	"master", "Master", "MASTER",
-	"rc", "RC",
-	"alpha", "Alpha", "ALPHA",
-	"beta", "Beta", "BETA",
-	"dev", "DEV",
-	"unstable", "Unstable", "UNSTABLE",
-	"eap", "EAP",
-	"donotuse",
+// SetScheme sets the version scheme used to order results, unless the config overrides it
+func (rs *ResultSet) SetScheme(s version.Scheme) {
+	if !rs.fixedScheme {
+		rs.scheme = s
+	}
 }
 
-// AddResult appends a new Result
+// SetNormalizer sets the rules used to normalize the versions of new results, unless the config overrides them
+func (rs *ResultSet) SetNormalizer(n version.Normalizer) {
+	if !rs.fixedNormalizer {
+		rs.normalizer = n
+	}
+}
+
+// Scheme gets the version scheme used to order results
+func (rs *ResultSet) Scheme() version.Scheme {
+	return rs.scheme
+}
+
+// AddResult normalizes and appends a new Result, unless it is less stable than the current Channel
 func (rs *ResultSet) AddResult(r *Result) {
-	if r == nil || r.Version[0] == "N/A" {
+	if r == nil {
 		return
 	}
-	for _, part := range r.Version {
-		for _, skip := range skipWords {
-			if part == skip {
-				return
-			}
-		}
+	if len(rs.normalizer) > 0 {
+		r.Normalize(rs.normalizer)
+	}
+	if r.Version[0] == "N/A" || !r.Accepted() {
+		return
 	}
 	rs.results = append(rs.results, r)
 }
 
+// Filter removes every Result that doesn't satisfy a constraint, using the version scheme of this set
+func (rs *ResultSet) Filter(c version.Constraint) {
+	kept := rs.results[:0]
+	for _, r := range rs.results {
+		if c.Check(r.Raw, rs.scheme) {
+			kept = append(kept, r)
+		}
+	}
+	rs.results = kept
+}
+
 // First retrieves the first result from a query
 func (rs *ResultSet) First() *Result {
 	sort.Sort(rs)
@@ -73,6 +117,16 @@ func (rs *ResultSet) Last() *Result {
 	return rs.results[len(rs.results)-1]
 }
 
+// Newest retrieves up to "n" of the most recent results from a query, newest first
+func (rs *ResultSet) Newest(n int) []*Result {
+	sort.Sort(rs)
+	newest := make([]*Result, 0, n)
+	for i := len(rs.results) - 1; i >= 0 && len(newest) < n; i-- {
+		newest = append(newest, rs.results[i])
+	}
+	return newest
+}
+
 // PrintAll pretty-prints an entire ResultSet
 func (rs *ResultSet) PrintAll() {
 	fmt.Printf("%s: '%s'\n", "Results of Query", rs.query)
@@ -91,8 +145,16 @@ func (rs *ResultSet) Len() int {
 // Less reports whether the element with
 // index i should sort before the element with index j. (sort.Interface)
 func (rs *ResultSet) Less(i, j int) bool {
-	if !rs.results[i].Published.IsZero() && !rs.results[j].Published.IsZero() {
-		return rs.results[i].Published.Before(rs.results[j].Published)
+	ri, rj := rs.results[i].Raw, rs.results[j].Raw
+	// Versions that follow a specific scheme are ordered by it, rather than by when they were published
+	if rs.scheme != version.Generic && rs.scheme.Valid(ri) && rs.scheme.Valid(rj) {
+		if c := rs.scheme.Compare(ri, rj); c != 0 {
+			return c < 0
+		}
+	}
+	pi, pj := rs.results[i].Published, rs.results[j].Published
+	if !pi.IsZero() && !pj.IsZero() && !pi.Equal(pj) {
+		return pi.Before(pj)
 	}
 	return !rs.results[i].Version.Less(rs.results[j].Version)
 }
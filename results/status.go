@@ -25,4 +25,6 @@ var (
 	NotFound = errors.New("not found")
 	// Unavailable - Provider could not be reached
 	Unavailable = errors.New("could not reach provider")
+	// RateLimited - Provider refused the query until the rate limit resets
+	RateLimited = errors.New("rate limited by provider")
 )
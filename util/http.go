@@ -20,37 +20,97 @@ import (
 	"encoding/json"
 	"github.com/DataDrake/cuppa/results"
 	log "github.com/DataDrake/waterlog"
+	"io"
 	"net/http"
+	"regexp"
 )
 
+// NextRegex matches the next page in a "Link" header
+var NextRegex = regexp.MustCompile("<([^>]+)>;\\s*rel=\"next\"")
+
+// Fetch requests from a URL and returns the message body if the request succeeds
+func Fetch(url, kind string) (body io.ReadCloser, err error) {
+	resp, err := fetch(url, kind, nil)
+	if err == nil {
+		body = resp.Body
+	}
+	return
+}
+
 // FetchJSON requests from a URL and converts the message body from JSON to a desired type
 func FetchJSON(url, kind string, out interface{}) error {
+	_, err := FetchJSONPage(url, kind, nil, out)
+	return err
+}
+
+// FetchJSONPage requests from a URL with additional headers, converts the message body from JSON
+// to a desired type, and returns the location of the next page from the "Link" header, if any
+func FetchJSONPage(url, kind string, headers map[string]string, out interface{}) (next string, err error) {
+	hs := map[string]string{"Accept": "application/json"}
+	for k, v := range headers {
+		hs[k] = v
+	}
+	resp, err := fetch(url, kind, hs)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	// Decode response
+	dec := json.NewDecoder(resp.Body)
+	if err = dec.Decode(out); err != nil {
+		log.Debugf("Failed to decode response: %s\n", err)
+		err = results.Unavailable
+		return
+	}
+	if sm := NextRegex.FindStringSubmatch(resp.Header.Get("Link")); len(sm) > 1 {
+		next = sm[1]
+	}
+	return
+}
+
+func fetch(url, kind string, headers map[string]string) (resp *http.Response, err error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		log.Debugf("Failed to build request: %s\n", err)
-		return results.Unavailable
+		err = results.Unavailable
+		return
 	}
-	req.Header.Set("Accept", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	for k, v := range headers {
+		if len(v) > 0 {
+			req.Header.Set(k, v)
+		}
+	}
+	Wait(url)
+	resp, err = http.DefaultClient.Do(req)
 	if err != nil {
 		log.Debugf("Failed to get %s: %s\n", kind, err)
-		return results.Unavailable
+		err = results.Unavailable
+		return
 	}
-	defer resp.Body.Close()
 	// Translate Status Code
 	switch resp.StatusCode {
 	case 200:
-		break
+		return
 	case 404:
-		return results.NotFound
+		err = results.NotFound
 	default:
-		return results.Unavailable
+		err = results.Unavailable
+		if IsRateLimited(resp) {
+			err = results.RateLimited
+		}
 	}
-	// Decode response
-	dec := json.NewDecoder(resp.Body)
-	if err = dec.Decode(out); err != nil {
-		log.Debugf("Failed to decode response: %s\n", err)
-		return results.Unavailable
+	resp.Body.Close()
+	resp = nil
+	return
+}
+
+// IsRateLimited checks if a response was refused because of a rate limit
+func IsRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case 429:
+		return true
+	case 403:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0"
 	}
-	return nil
+	return false
 }
@@ -0,0 +1,62 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// limiter spaces out requests to a single host
+type limiter struct {
+	sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+var (
+	limits     = make(map[string]*limiter)
+	limitsLock sync.Mutex
+)
+
+// SetRateLimit allows at most one request to a host per interval, shared by every provider
+func SetRateLimit(host string, interval time.Duration) {
+	limitsLock.Lock()
+	limits[host] = &limiter{interval: interval}
+	limitsLock.Unlock()
+}
+
+// Wait blocks until a request to the host of "raw" is allowed
+func Wait(raw string) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return
+	}
+	limitsLock.Lock()
+	l := limits[u.Hostname()]
+	limitsLock.Unlock()
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+	if delay := time.Until(l.next); delay > 0 {
+		time.Sleep(delay)
+	}
+	l.next = time.Now().Add(l.interval)
+}
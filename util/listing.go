@@ -0,0 +1,86 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"bufio"
+	"github.com/DataDrake/cuppa/results"
+	log "github.com/DataDrake/waterlog"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// LinkRegex matches a file or directory link in an HTTP directory listing
+	LinkRegex = regexp.MustCompile("href=\"([^\"?]+)\"")
+	// DateRegex matches a modification time in an HTTP directory listing
+	DateRegex = regexp.MustCompile("(\\d{4}-\\d{2}-\\d{2} \\d{2}:\\d{2}|\\d{2}-[A-Z][a-z]{2}-\\d{4} \\d{2}:\\d{2})")
+)
+
+// DateLayouts are the modification time formats used by common HTTP directory listings
+var DateLayouts = []string{"2006-01-02 15:04", "02-Jan-2006 15:04"}
+
+// Entry is a single file or directory in a directory listing
+type Entry struct {
+	Name string
+	Dir  bool
+	Time time.Time
+}
+
+// ListHTTP gets the files and directories in an HTTP directory listing, one entry per line
+func ListHTTP(url string) (entries []Entry, err error) {
+	body, err := Fetch(url, "listing")
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		sm := LinkRegex.FindStringSubmatch(line)
+		if len(sm) != 2 {
+			continue
+		}
+		name := sm[1]
+		// Skip sorting, parent and absolute links
+		if strings.HasPrefix(name, "#") || strings.HasPrefix(name, ".") || strings.Contains(name, "://") || strings.HasPrefix(name, "/") {
+			continue
+		}
+		entry := Entry{
+			Name: strings.TrimSuffix(name, "/"),
+			Dir:  strings.HasSuffix(name, "/"),
+		}
+		if strings.Contains(entry.Name, "/") {
+			continue
+		}
+		if date := DateRegex.FindString(line); len(date) > 0 {
+			for _, layout := range DateLayouts {
+				if t, e := time.Parse(layout, date); e == nil {
+					entry.Time = t
+					break
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		log.Debugf("Failed to read listing: %s\n", err)
+		err = results.Unavailable
+	}
+	return
+}
@@ -0,0 +1,87 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"github.com/DataDrake/cuppa/config"
+	log "github.com/DataDrake/waterlog"
+	"regexp"
+	"strings"
+)
+
+// TagFilter selects the tags of a single component of a monorepo, like "cli/v2.3.0" or "libfoo-1.4.2"
+type TagFilter struct {
+	Prefix  string
+	Pattern *regexp.Regexp
+}
+
+// NewTagFilter gets the tag filter configured for the first of the names that has one
+func NewTagFilter(names ...string) (f TagFilter) {
+	tags := config.Global.Tags
+	for _, name := range names {
+		prefix, hasPrefix := tags.Prefixes[name]
+		raw, hasPattern := tags.Patterns[name]
+		if !hasPrefix && !hasPattern {
+			continue
+		}
+		f.Prefix = prefix
+		if len(raw) > 0 {
+			pattern, err := regexp.Compile(raw)
+			if err != nil {
+				log.Warnf("Invalid tag pattern for %s: %s\n", name, err)
+				return
+			}
+			f.Pattern = pattern
+		}
+		return
+	}
+	return
+}
+
+// IsSet checks if this filter selects any tags at all
+func (f TagFilter) IsSet() bool {
+	return len(f.Prefix) > 0 || f.Pattern != nil
+}
+
+// Version checks that a tag belongs to this component, and strips it down to the version to be parsed
+//
+// Patterns may pick out the version with a group named "version", or else with their first group.
+func (f TagFilter) Version(tag string) (version string, ok bool) {
+	if !strings.HasPrefix(tag, f.Prefix) {
+		return
+	}
+	version = strings.TrimPrefix(tag, f.Prefix)
+	if f.Pattern == nil {
+		ok = true
+		return
+	}
+	sm := f.Pattern.FindStringSubmatch(version)
+	if sm == nil {
+		version = ""
+		return
+	}
+	ok = true
+	if len(sm) > 1 {
+		version = sm[1]
+	}
+	for i, name := range f.Pattern.SubexpNames() {
+		if name == "version" {
+			version = sm[i]
+		}
+	}
+	return
+}
@@ -0,0 +1,54 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"regexp"
+	"testing"
+)
+
+func tagFilterTest(t *testing.T, f TagFilter, tag, expected string, match bool) {
+	version, ok := f.Version(tag)
+	if ok != match {
+		t.Errorf("Expected match '%t' for tag '%s', found '%t'", match, tag, ok)
+	}
+	if version != expected {
+		t.Errorf("Expected version '%s' for tag '%s', found '%s'", expected, tag, version)
+	}
+}
+
+func TestTagFilterNone(t *testing.T) {
+	tagFilterTest(t, TagFilter{}, "cli/v2.3.0", "cli/v2.3.0", true)
+}
+
+func TestTagFilterPrefix(t *testing.T) {
+	f := TagFilter{Prefix: "cli/v"}
+	tagFilterTest(t, f, "cli/v2.3.0", "2.3.0", true)
+	tagFilterTest(t, f, "libfoo-1.4.2", "", false)
+}
+
+func TestTagFilterPattern(t *testing.T) {
+	f := TagFilter{Pattern: regexp.MustCompile("^libfoo-(\\d.*)$")}
+	tagFilterTest(t, f, "libfoo-1.4.2", "1.4.2", true)
+	tagFilterTest(t, f, "libfoo-bar-1.0", "", false)
+}
+
+func TestTagFilterNamed(t *testing.T) {
+	f := TagFilter{Pattern: regexp.MustCompile("^(rel|release)-(?P<version>\\d{4}\\.\\d{2})$")}
+	tagFilterTest(t, f, "release-2023.05", "2023.05", true)
+	tagFilterTest(t, f, "release-2023.05.1", "", false)
+}
@@ -0,0 +1,104 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MinYear is the earliest year accepted in a calendar version
+	MinYear = 1970
+	// MaxYear is the latest year accepted in a calendar version
+	MaxYear = 2999
+)
+
+// CalVerRegex matches a calendar version, like "2024.10", "24.04.1" or "20231015.2"
+var CalVerRegex = regexp.MustCompile("^v?(?:(\\d{4})(\\d{2})(\\d{2})|(\\d{4}|\\d{2}))((?:[._-]\\d+)*)$")
+
+// calVerSeparators split the components after the year of a calendar version
+var calVerSeparators = regexp.MustCompile("[._-]")
+
+// parseCalVer splits a calendar version into its four-digit year and the components that follow it,
+// where two-digit years are always in the 2000s and must be followed by at least one component
+func parseCalVer(raw string) (pieces []string, ok bool) {
+	sm := CalVerRegex.FindStringSubmatch(strings.TrimSpace(raw))
+	if sm == nil {
+		return
+	}
+	if len(sm[1]) > 0 {
+		month, _ := strconv.Atoi(sm[2])
+		day, _ := strconv.Atoi(sm[3])
+		if month < 1 || month > 12 || day < 1 || day > 31 {
+			return
+		}
+		pieces = []string{sm[1], sm[2], sm[3]}
+	} else {
+		year := sm[4]
+		if len(year) == 2 {
+			if len(sm[5]) == 0 {
+				return
+			}
+			year = "20" + year
+		}
+		pieces = []string{year}
+	}
+	if year, _ := strconv.Atoi(pieces[0]); year < MinYear || year > MaxYear {
+		return
+	}
+	if len(sm[5]) > 0 {
+		pieces = append(pieces, calVerSeparators.Split(sm[5][1:], -1)...)
+	}
+	ok = true
+	return
+}
+
+type calVer struct{}
+
+func (s calVer) String() string {
+	return "calver"
+}
+
+func (s calVer) Valid(raw string) bool {
+	_, ok := parseCalVer(raw)
+	return ok
+}
+
+// Compare orders by year and then by each following component, so "24.04" and "2024.04" are equal
+// and "20231015" is older than "2023.11"
+func (s calVer) Compare(a, b string) int {
+	ap, aok := parseCalVer(a)
+	bp, bok := parseCalVer(b)
+	if !aok || !bok {
+		return Generic.Compare(a, b)
+	}
+	for i := 0; i < len(ap) || i < len(bp); i++ {
+		var ac, bc string
+		if i < len(ap) {
+			ac = ap[i]
+		}
+		if i < len(bp) {
+			bc = bp[i]
+		}
+		if c := compareNumeric(ac, bc); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
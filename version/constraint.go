@@ -0,0 +1,213 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TermRegex matches a single term of a constraint, like ">= 1.2", "~> 2.2", "^0.3" or "3.11.*"
+var TermRegex = regexp.MustCompile("^(~>|\\^|~|>=|<=|!=|==|=|>|<)?\\s*(v?[0-9A-Za-z.*+_-]+)$")
+
+// term is a comparison with a single version
+type term struct {
+	op      string
+	version string
+	// series are the leading pieces of a wildcard version, like "3.11" for "3.11.*"
+	series   Version
+	wildcard bool
+}
+
+// Constraint is a set of requirements on versions, where alternatives are separated by "||" and the
+// terms of each alternative are separated by ","
+type Constraint struct {
+	alternatives [][]term
+}
+
+// ParseConstraint parses a constraint, like ">= 1.2, < 2", "~> 2.2", "^0.3" or "3.11.*"
+func ParseConstraint(raw string) (c Constraint, err error) {
+	if len(strings.TrimSpace(raw)) == 0 {
+		return
+	}
+	for _, alt := range strings.Split(raw, "||") {
+		var terms []term
+		for _, piece := range strings.Split(alt, ",") {
+			var more []term
+			if more, err = parseTerm(strings.TrimSpace(piece)); err != nil {
+				return
+			}
+			terms = append(terms, more...)
+		}
+		c.alternatives = append(c.alternatives, terms)
+	}
+	return
+}
+
+// parseTerm parses a single term, expanding the range operators into lower and upper bounds
+func parseTerm(raw string) (terms []term, err error) {
+	sm := TermRegex.FindStringSubmatch(raw)
+	if sm == nil {
+		err = fmt.Errorf("invalid constraint '%s'", raw)
+		return
+	}
+	op, v := sm[1], sm[2]
+	pieces := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	last := len(pieces) - 1
+	for _, piece := range pieces[:last] {
+		if isWildcard(piece) {
+			err = fmt.Errorf("invalid wildcard '%s'", v)
+			return
+		}
+	}
+	if isWildcard(pieces[last]) {
+		switch op {
+		case "", "=", "==", "!=":
+		default:
+			err = fmt.Errorf("wildcard '%s' cannot be used with '%s'", v, op)
+			return
+		}
+		terms = append(terms, term{op: op, version: v, series: pieces[:last], wildcard: true})
+		return
+	}
+	var numbers []string
+	for _, piece := range NewVersion(v) {
+		if !isDigits(piece) {
+			break
+		}
+		numbers = append(numbers, piece)
+	}
+	if len(numbers) == 0 {
+		err = fmt.Errorf("invalid version '%s'", v)
+		return
+	}
+	var upper []string
+	switch op {
+	case "~>":
+		// Everything up to the next release of the second to last piece, like "~> 2.2" for "< 3"
+		upper = bump(numbers, len(numbers)-2)
+	case "^":
+		// Everything up to the next release of the first non-zero piece, like "^0.2.3" for "< 0.3"
+		i := 0
+		for i < len(numbers)-1 && strings.Trim(numbers[i], "0") == "" {
+			i++
+		}
+		upper = bump(numbers, i)
+	case "~":
+		// Everything up to the next minor release, like "~1.2.3" for "< 1.3"
+		upper = bump(numbers, 1)
+	default:
+		if op == "=" {
+			op = "=="
+		}
+		terms = append(terms, term{op: op, version: v})
+		return
+	}
+	terms = append(terms, term{op: ">=", version: v}, term{op: "<", version: strings.Join(upper, ".")})
+	return
+}
+
+// isWildcard checks if a piece of a version matches anything
+func isWildcard(piece string) bool {
+	return piece == "*" || piece == "x" || piece == "X"
+}
+
+// bump increments the piece at an index, dropping every piece after it
+func bump(pieces []string, i int) []string {
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(pieces) {
+		i = len(pieces) - 1
+	}
+	next, _ := strconv.Atoi(pieces[i])
+	return append(append([]string{}, pieces[:i]...), strconv.Itoa(next+1))
+}
+
+// IsEmpty checks if this constraint accepts every version
+func (c Constraint) IsEmpty() bool {
+	return len(c.alternatives) == 0
+}
+
+// Check checks if a raw version satisfies this constraint, ordering versions with a scheme
+func (c Constraint) Check(raw string, s Scheme) bool {
+	if c.IsEmpty() {
+		return true
+	}
+	for _, terms := range c.alternatives {
+		ok := true
+		for _, t := range terms {
+			if ok = t.check(raw, s); !ok {
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// check checks if a raw version satisfies a single term
+func (t term) check(raw string, s Scheme) bool {
+	if t.wildcard {
+		return t.inSeries(raw) == (t.op != "!=")
+	}
+	c := s.Compare(raw, t.version)
+	switch t.op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case "!=":
+		return c != 0
+	}
+	return c == 0
+}
+
+// inSeries checks if a raw version starts with the pieces of a wildcard
+func (t term) inSeries(raw string) bool {
+	v := NewVersion(raw)
+	if len(v) < len(t.series) {
+		return false
+	}
+	for i, piece := range t.series {
+		if piece != v[i] && !(isDigits(piece) && isDigits(v[i]) && compareNumeric(piece, v[i]) == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// String gives the text of this constraint
+func (c Constraint) String() string {
+	var alts []string
+	for _, terms := range c.alternatives {
+		var parts []string
+		for _, t := range terms {
+			parts = append(parts, t.op+t.version)
+		}
+		alts = append(alts, strings.Join(parts, ", "))
+	}
+	return strings.Join(alts, " || ")
+}
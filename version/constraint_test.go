@@ -0,0 +1,75 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"testing"
+)
+
+func constraintTest(t *testing.T, raw string, s Scheme, matches, misses []string) {
+	c, err := ParseConstraint(raw)
+	if err != nil {
+		t.Fatalf("Failed to parse '%s': %s", raw, err)
+	}
+	for _, v := range matches {
+		if !c.Check(v, s) {
+			t.Errorf("Expected '%s' to satisfy '%s'", v, raw)
+		}
+	}
+	for _, v := range misses {
+		if c.Check(v, s) {
+			t.Errorf("Expected '%s' not to satisfy '%s'", v, raw)
+		}
+	}
+}
+
+func TestConstraintRange(t *testing.T) {
+	constraintTest(t, ">= 1.2, < 2", Generic, []string{"1.2", "1.10.3", "v1.99"}, []string{"1.1.9", "2.0", "10.0"})
+}
+
+func TestConstraintPessimistic(t *testing.T) {
+	constraintTest(t, "~> 2.2", Generic, []string{"2.2", "2.9.1"}, []string{"2.1", "3.0"})
+	constraintTest(t, "~> 2.2.0", Generic, []string{"2.2.0", "2.2.9"}, []string{"2.3.0", "2.1.9"})
+}
+
+func TestConstraintCaret(t *testing.T) {
+	constraintTest(t, "^1.2.3", SemVer, []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "2.0.0-rc.1"})
+	constraintTest(t, "^0.2.3", SemVer, []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "1.0.0"})
+	constraintTest(t, "^0.0.3", SemVer, []string{"0.0.3"}, []string{"0.0.4", "0.1.0"})
+}
+
+func TestConstraintTilde(t *testing.T) {
+	constraintTest(t, "~1.2.3", Generic, []string{"1.2.3", "1.2.10"}, []string{"1.3.0", "1.2.2"})
+}
+
+func TestConstraintWildcard(t *testing.T) {
+	constraintTest(t, "3.11.*", PEP440, []string{"3.11.0", "3.11.9"}, []string{"3.1.1", "3.12.0", "3.110.0"})
+	constraintTest(t, "!= 3.x", Generic, []string{"2.7", "4.0"}, []string{"3.0", "3.9.1"})
+	constraintTest(t, "*", Generic, []string{"1.0", "2024.10"}, nil)
+}
+
+func TestConstraintAlternatives(t *testing.T) {
+	constraintTest(t, "< 1.0 || >= 2.0, != 2.1", Generic, []string{"0.9", "2.0", "2.2"}, []string{"1.5", "2.1"})
+}
+
+func TestConstraintInvalid(t *testing.T) {
+	for _, raw := range []string{">= 3.*", "~> abc", "1.*.3", ">>1"} {
+		if _, err := ParseConstraint(raw); err == nil {
+			t.Errorf("Expected '%s' to be invalid", raw)
+		}
+	}
+}
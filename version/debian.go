@@ -0,0 +1,145 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// DebianUpstreamRegex matches the upstream part of a Debian version
+	DebianUpstreamRegex = regexp.MustCompile("^[0-9][A-Za-z0-9.+~-]*$")
+	// DebianRevisionRegex matches the Debian revision of a Debian version
+	DebianRevisionRegex = regexp.MustCompile("^[A-Za-z0-9.+~]+$")
+)
+
+// debianVersion is a Debian version split into "[epoch:]upstream[-revision]"
+type debianVersion struct {
+	epoch    string
+	upstream string
+	revision string
+}
+
+func parseDebian(raw string) (v debianVersion, ok bool) {
+	raw = strings.TrimSpace(raw)
+	if i := strings.Index(raw, ":"); i >= 0 {
+		if v.epoch = raw[:i]; !isDigits(v.epoch) {
+			return
+		}
+		raw = raw[i+1:]
+	}
+	v.upstream = raw
+	if i := strings.LastIndex(raw, "-"); i >= 0 {
+		v.upstream, v.revision = raw[:i], raw[i+1:]
+		if !DebianRevisionRegex.MatchString(v.revision) {
+			return
+		}
+	}
+	ok = DebianUpstreamRegex.MatchString(v.upstream)
+	return
+}
+
+type debian struct{}
+
+func (s debian) String() string {
+	return "debian"
+}
+
+func (s debian) Valid(raw string) bool {
+	_, ok := parseDebian(raw)
+	return ok
+}
+
+// Compare orders by epoch, upstream version and Debian revision
+func (s debian) Compare(a, b string) int {
+	av, aok := parseDebian(a)
+	bv, bok := parseDebian(b)
+	if !aok || !bok {
+		return Generic.Compare(a, b)
+	}
+	if c := compareNumeric(av.epoch, bv.epoch); c != 0 {
+		return c
+	}
+	if c := verrevcmp(av.upstream, bv.upstream); c != 0 {
+		return c
+	}
+	return verrevcmp(av.revision, bv.revision)
+}
+
+// debianOrder ranks a single non-digit character, where "~" sorts before anything, even the end
+func debianOrder(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return 0
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	case c == '~':
+		return -1
+	case c != 0:
+		return int(c) + 256
+	}
+	return 0
+}
+
+// verrevcmp compares alternating non-digit and digit runs, the same as dpkg
+func verrevcmp(a, b string) int {
+	at := func(s string, i int) byte {
+		if i < len(s) {
+			return s[i]
+		}
+		return 0
+	}
+	isDigit := func(c byte) bool {
+		return c >= '0' && c <= '9'
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := debianOrder(at(a, i)), debianOrder(at(b, j))
+			if ac != bc {
+				return ac - bc
+			}
+			i++
+			j++
+		}
+		for at(a, i) == '0' {
+			i++
+		}
+		for at(b, j) == '0' {
+			j++
+		}
+		diff := 0
+		for isDigit(at(a, i)) && isDigit(at(b, j)) {
+			if diff == 0 {
+				diff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if isDigit(at(a, i)) {
+			return 1
+		}
+		if isDigit(at(b, j)) {
+			return -1
+		}
+		if diff != 0 {
+			return diff
+		}
+	}
+	return 0
+}
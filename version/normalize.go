@@ -0,0 +1,104 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Rule is a single step in normalizing a raw version before it is parsed
+type Rule func(raw string) string
+
+// Normalizer is a pipeline of rules, applied in order
+type Normalizer []Rule
+
+// Normalize applies every rule to a raw version
+func (n Normalizer) Normalize(raw string) string {
+	for _, rule := range n {
+		raw = rule(raw)
+	}
+	return raw
+}
+
+// StripPrefix drops any vendor prefix before the first digit, like "v", "R_", "release-" or "OTP-"
+func StripPrefix(raw string) string {
+	if i := strings.IndexFunc(raw, unicode.IsDigit); i > 0 {
+		return raw[i:]
+	}
+	return raw
+}
+
+// StripText drops a specific prefix, like "OTP-"
+func StripText(prefix string) Rule {
+	return func(raw string) string {
+		return strings.TrimPrefix(raw, prefix)
+	}
+}
+
+// UnderscoreAsDot treats underscores as dots in versions without any dots, like "8_4_0"
+func UnderscoreAsDot(raw string) string {
+	if strings.Contains(raw, ".") {
+		return raw
+	}
+	return strings.Replace(raw, "_", ".", -1)
+}
+
+// DropBuild drops build metadata, like the "+ds" of "1.2.3+ds"
+func DropBuild(raw string) string {
+	if i := strings.Index(raw, "+"); i > 0 {
+		return raw[:i]
+	}
+	return raw
+}
+
+// DropSuffix drops any letters after the last digit, like the "a" of "1.2.3a"
+func DropSuffix(raw string) string {
+	if i := strings.LastIndexFunc(raw, unicode.IsDigit); i >= 0 {
+		return raw[:i+1]
+	}
+	return raw
+}
+
+// TagRules are the rules for versions taken from VCS tags, like "curl-8_4_0" or "R_2_5_0"
+var TagRules = Normalizer{StripPrefix, UnderscoreAsDot}
+
+// Rules are the available rules by name
+var Rules = map[string]Rule{
+	"strip-prefix":      StripPrefix,
+	"underscore-as-dot": UnderscoreAsDot,
+	"drop-build":        DropBuild,
+	"drop-suffix":       DropSuffix,
+}
+
+// ParseRules builds a normalizer from rule names, where "strip-prefix:<text>" drops a specific prefix
+func ParseRules(names []string) (n Normalizer, err error) {
+	for _, name := range names {
+		if strings.HasPrefix(name, "strip-prefix:") {
+			n = append(n, StripText(strings.TrimPrefix(name, "strip-prefix:")))
+			continue
+		}
+		rule, ok := Rules[name]
+		if !ok {
+			err = fmt.Errorf("unknown normalization rule '%s'", name)
+			return
+		}
+		n = append(n, rule)
+	}
+	return
+}
@@ -0,0 +1,49 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"testing"
+)
+
+func normalizeTest(t *testing.T, n Normalizer, raw, expected string) {
+	if found := n.Normalize(raw); found != expected {
+		t.Errorf("Expected '%s' to normalize to '%s', found '%s'", raw, expected, found)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	normalizeTest(t, TagRules, "v1_2_3", "1.2.3")
+	normalizeTest(t, TagRules, "R_2_5_0", "2.5.0")
+	normalizeTest(t, TagRules, "release-1.2", "1.2")
+	normalizeTest(t, TagRules, "OTP-26.1", "26.1")
+	normalizeTest(t, TagRules, "curl-8_4_0", "8.4.0")
+	normalizeTest(t, TagRules, "1.0_beta.1", "1.0_beta.1")
+}
+
+func TestNormalizeRules(t *testing.T) {
+	n, err := ParseRules([]string{"strip-prefix:OTP-", "drop-build", "drop-suffix"})
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %s", err)
+	}
+	normalizeTest(t, n, "OTP-26.1", "26.1")
+	normalizeTest(t, n, "1.2.3+ds", "1.2.3")
+	normalizeTest(t, n, "1.2.3a", "1.2.3")
+	if _, err = ParseRules([]string{"bogus"}); err == nil {
+		t.Error("Expected 'bogus' to be an unknown rule")
+	}
+}
@@ -0,0 +1,179 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PEP440Regex matches any PEP 440 version, including the spellings allowed by normalization
+var PEP440Regex = regexp.MustCompile("(?i)^v?(?:([0-9]+)!)?([0-9]+(?:\\.[0-9]+)*)" +
+	"(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?([0-9]+)?)?" +
+	"(?:-([0-9]+)|[-_.]?(post|rev|r)[-_.]?([0-9]+)?)?" +
+	"(?:[-_.]?(dev)[-_.]?([0-9]+)?)?" +
+	"(?:\\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$")
+
+// pep440Separators split the segments of a local version
+var pep440Separators = regexp.MustCompile("[-_.]")
+
+const (
+	// pep440Dev ranks developmental releases of a final release, like "1.0.dev1"
+	pep440Dev = -1
+	// pep440Final ranks releases that are not pre-releases
+	pep440Final = 3
+)
+
+// pep440Pre ranks the pre-release phases, after normalization
+var pep440Pre = map[string]int{
+	"a": 0, "alpha": 0,
+	"b": 1, "beta": 1,
+	"c": 2, "rc": 2, "pre": 2, "preview": 2,
+}
+
+// pep440Version is a parsed PEP 440 version, ready for comparison
+type pep440Version struct {
+	epoch   string
+	release []string
+	// prePhase is the rank of a pre-release phase, with developmental releases of a final release
+	// before every phase and final releases after them
+	prePhase int
+	pre      string
+	post     string
+	hasPost  bool
+	dev      string
+	hasDev   bool
+	local    []string
+}
+
+func parsePEP440(raw string) (v pep440Version, ok bool) {
+	sm := PEP440Regex.FindStringSubmatch(strings.TrimSpace(raw))
+	if sm == nil {
+		return
+	}
+	ok = true
+	v.epoch = sm[1]
+	v.release = strings.Split(sm[2], ".")
+	// Trailing zeros have no effect on ordering
+	for len(v.release) > 1 && strings.Trim(v.release[len(v.release)-1], "0") == "" {
+		v.release = v.release[:len(v.release)-1]
+	}
+	v.prePhase = pep440Final
+	if len(sm[3]) > 0 {
+		v.prePhase = pep440Pre[strings.ToLower(sm[3])]
+		v.pre = sm[4]
+	}
+	if len(sm[5]) > 0 || len(sm[6]) > 0 {
+		v.hasPost = true
+		v.post = sm[5] + sm[7]
+	}
+	if len(sm[8]) > 0 {
+		v.hasDev = true
+		v.dev = sm[9]
+	}
+	// A developmental release of a final release comes before its pre-releases
+	if v.prePhase == pep440Final && !v.hasPost && v.hasDev {
+		v.prePhase = pep440Dev
+	}
+	if len(sm[10]) > 0 {
+		v.local = pep440Separators.Split(strings.ToLower(sm[10]), -1)
+	}
+	return
+}
+
+type pep440 struct{}
+
+func (s pep440) String() string {
+	return "pep440"
+}
+
+func (s pep440) Valid(raw string) bool {
+	_, ok := parsePEP440(raw)
+	return ok
+}
+
+// Compare orders by epoch, release, pre-release, post-release, developmental release and local version
+func (s pep440) Compare(a, b string) int {
+	av, aok := parsePEP440(a)
+	bv, bok := parsePEP440(b)
+	if !aok || !bok {
+		return Generic.Compare(a, b)
+	}
+	if c := compareNumeric(av.epoch, bv.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(av.release) || i < len(bv.release); i++ {
+		var ar, br string
+		if i < len(av.release) {
+			ar = av.release[i]
+		}
+		if i < len(bv.release) {
+			br = bv.release[i]
+		}
+		if c := compareNumeric(ar, br); c != 0 {
+			return c
+		}
+	}
+	// Final releases are newer than pre-releases, which are newer than developmental releases
+	if av.prePhase != bv.prePhase {
+		return av.prePhase - bv.prePhase
+	}
+	if c := compareNumeric(av.pre, bv.pre); c != 0 {
+		return c
+	}
+	if av.hasPost != bv.hasPost {
+		if av.hasPost {
+			return 1
+		}
+		return -1
+	}
+	if c := compareNumeric(av.post, bv.post); c != 0 {
+		return c
+	}
+	if av.hasDev != bv.hasDev {
+		if av.hasDev {
+			return -1
+		}
+		return 1
+	}
+	if c := compareNumeric(av.dev, bv.dev); c != 0 {
+		return c
+	}
+	return comparePEP440Local(av.local, bv.local)
+}
+
+// comparePEP440Local orders local versions, where numeric segments are newer than alphanumeric ones
+func comparePEP440Local(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		an, bn := isDigits(a[i]), isDigits(b[i])
+		var c int
+		switch {
+		case an && bn:
+			c = compareNumeric(a[i], b[i])
+		case an:
+			c = 1
+		case bn:
+			c = -1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
@@ -0,0 +1,141 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RPMRegex matches an RPM "[epoch:]version[-release]"
+var RPMRegex = regexp.MustCompile("^(?:([0-9]+):)?([^-:\\s]+)(?:-([^-:\\s]+))?$")
+
+type rpm struct{}
+
+func (s rpm) String() string {
+	return "rpm"
+}
+
+func (s rpm) Valid(raw string) bool {
+	return RPMRegex.MatchString(strings.TrimSpace(raw))
+}
+
+// Compare orders by epoch, version and release
+func (s rpm) Compare(a, b string) int {
+	as := RPMRegex.FindStringSubmatch(strings.TrimSpace(a))
+	bs := RPMRegex.FindStringSubmatch(strings.TrimSpace(b))
+	if as == nil || bs == nil {
+		return Generic.Compare(a, b)
+	}
+	if c := compareNumeric(as[1], bs[1]); c != 0 {
+		return c
+	}
+	if c := rpmvercmp(as[2], bs[2]); c != 0 {
+		return c
+	}
+	return rpmvercmp(as[3], bs[3])
+}
+
+// rpmvercmp compares alphabetic and numeric segments, the same as RPM
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	isDigit := func(c byte) bool {
+		return c >= '0' && c <= '9'
+	}
+	isAlpha := func(c byte) bool {
+		return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+	}
+	// skip drops the separators at the start of a string
+	skip := func(s string) string {
+		for len(s) > 0 && !isDigit(s[0]) && !isAlpha(s[0]) && s[0] != '~' && s[0] != '^' {
+			s = s[1:]
+		}
+		return s
+	}
+	for len(a) > 0 || len(b) > 0 {
+		a, b = skip(a), skip(b)
+		// A tilde sorts before anything, even the end of the version
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		// A caret sorts after the end of the version, but before anything else
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			switch {
+			case len(a) == 0:
+				return -1
+			case len(b) == 0:
+				return 1
+			case a[0] != '^':
+				return 1
+			case b[0] != '^':
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+		if len(a) == 0 || len(b) == 0 {
+			break
+		}
+		same := isAlpha
+		if isDigit(a[0]) {
+			same = isDigit
+		}
+		i, j := 0, 0
+		for i < len(a) && same(a[i]) {
+			i++
+		}
+		for j < len(b) && same(b[j]) {
+			j++
+		}
+		// Numeric segments are newer than alphabetic ones
+		if j == 0 {
+			if isDigit(a[0]) {
+				return 1
+			}
+			return -1
+		}
+		var c int
+		if isDigit(a[0]) {
+			c = compareNumeric(a[:i], b[:j])
+		} else {
+			c = strings.Compare(a[:i], b[:j])
+		}
+		if c != 0 {
+			if c < 0 {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[i:], b[j:]
+	}
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return -1
+	}
+	return 1
+}
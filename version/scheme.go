@@ -0,0 +1,113 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"strings"
+)
+
+// Scheme is a set of rules for parsing and ordering the version numbers of a package
+type Scheme interface {
+	// String gives the name of this scheme
+	String() string
+	// Valid checks if a raw version can be parsed with this scheme
+	Valid(raw string) bool
+	// Compare orders two raw versions, returning a negative number if "a" is older than "b",
+	// zero if they are equal, and a positive number if "a" is newer
+	Compare(a, b string) int
+}
+
+var (
+	// Generic is the ad-hoc scheme of NewVersion, which accepts any version at all
+	Generic Scheme = generic{}
+	// Numeric is the same as Generic, but orders by version alone instead of when releases were published
+	Numeric Scheme = numeric{}
+	// CalVer is for calendar versions, like "2024.10", "24.04.1" or "20231015"
+	CalVer Scheme = calVer{}
+	// SemVer is Semantic Versioning 2.0.0, allowing a leading "v"
+	SemVer Scheme = semVer{}
+	// PEP440 is the scheme for Python packages
+	PEP440 Scheme = pep440{}
+	// Debian is the scheme of "dpkg --compare-versions"
+	Debian Scheme = debian{}
+	// RPM is the scheme of "rpmvercmp", with epochs and releases
+	RPM Scheme = rpm{}
+)
+
+// Schemes are all of the available schemes by name
+var Schemes = map[string]Scheme{
+	Generic.String(): Generic,
+	Numeric.String(): Numeric,
+	CalVer.String():  CalVer,
+	SemVer.String():  SemVer,
+	PEP440.String():  PEP440,
+	Debian.String():  Debian,
+	RPM.String():     RPM,
+}
+
+// FindScheme gets a scheme by name, or nil if there is no such scheme
+func FindScheme(name string) Scheme {
+	return Schemes[strings.ToLower(name)]
+}
+
+// generic wraps NewVersion and Version.Compare
+type generic struct{}
+
+func (s generic) String() string {
+	return "generic"
+}
+
+func (s generic) Valid(raw string) bool {
+	return true
+}
+
+func (s generic) Compare(a, b string) int {
+	// Version.Compare is negative when the receiver is newer
+	return NewVersion(b).Compare(NewVersion(a))
+}
+
+// numeric is the generic scheme under another name
+type numeric struct {
+	generic
+}
+
+func (s numeric) String() string {
+	return "numeric"
+}
+
+// compareNumeric orders two strings of digits by value, without overflowing
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// isDigits checks if a string is made up entirely of ASCII digits
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"testing"
+)
+
+// schemeTest checks that each version is older than the one after it
+func schemeTest(t *testing.T, s Scheme, ordered ...string) {
+	for i := range ordered {
+		if !s.Valid(ordered[i]) {
+			t.Errorf("Expected '%s' to be valid for %s", ordered[i], s)
+		}
+		if c := s.Compare(ordered[i], ordered[i]); c != 0 {
+			t.Errorf("Expected '%s' to equal itself for %s, found '%d'", ordered[i], s, c)
+		}
+		if i == 0 {
+			continue
+		}
+		if c := s.Compare(ordered[i-1], ordered[i]); c >= 0 {
+			t.Errorf("Expected '%s' to be older than '%s' for %s, found '%d'", ordered[i-1], ordered[i], s, c)
+		}
+		if c := s.Compare(ordered[i], ordered[i-1]); c <= 0 {
+			t.Errorf("Expected '%s' to be newer than '%s' for %s, found '%d'", ordered[i], ordered[i-1], s, c)
+		}
+	}
+}
+
+func TestSchemeGeneric(t *testing.T) {
+	schemeTest(t, Generic, "1.2", "1.2.3", "1.10")
+}
+
+func TestSchemeSemVer(t *testing.T) {
+	schemeTest(t, SemVer, "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "v1.0.0", "1.0.1", "1.10.0")
+	if c := SemVer.Compare("1.0.0+build.1", "1.0.0+build.2"); c != 0 {
+		t.Errorf("Expected build metadata to be ignored, found '%d'", c)
+	}
+	if SemVer.Valid("1.0") {
+		t.Error("Expected '1.0' to be invalid for semver")
+	}
+}
+
+func TestSchemePEP440(t *testing.T) {
+	schemeTest(t, PEP440, "1.0.dev456", "1.0a1", "1.0a2.dev456", "1.0a12", "1.0b1.dev456", "1.0b2",
+		"1.0b2.post345", "1.0rc1", "1.0", "1.0+abc.5", "1.0+5", "1.0.post456.dev34", "1.0.post456",
+		"1.1.dev1", "1.1", "1!0.5")
+	if c := PEP440.Compare("1.0", "1.0.0"); c != 0 {
+		t.Errorf("Expected trailing zeros to be ignored, found '%d'", c)
+	}
+	if c := PEP440.Compare("1.0-1", "1.0.post1"); c != 0 {
+		t.Errorf("Expected implicit post releases to be equal, found '%d'", c)
+	}
+}
+
+func TestSchemeDebian(t *testing.T) {
+	schemeTest(t, Debian, "1.0~rc1", "1.0", "1.0-1", "1.0-1ubuntu1", "1.0a", "1.0+dfsg-1", "1.2.3-4",
+		"1.10", "1:0.9")
+	if Debian.Valid("abc") {
+		t.Error("Expected 'abc' to be invalid for debian")
+	}
+}
+
+func TestSchemeRPM(t *testing.T) {
+	schemeTest(t, RPM, "1.0~rc1", "1.0", "1.0^git1", "1.0a", "1.0.1", "1.0.1-2", "1.0.10", "1:0.1")
+	if c := RPM.Compare("1.0.01", "1.0.1"); c != 0 {
+		t.Errorf("Expected leading zeros to be ignored, found '%d'", c)
+	}
+}
+
+func TestSchemeCalVer(t *testing.T) {
+	schemeTest(t, CalVer, "2019.12", "20231015", "2023.11", "23.11.1", "24.04", "v2024.04.1", "2024.10",
+		"20241015.2")
+	if c := CalVer.Compare("24.04", "2024.04"); c != 0 {
+		t.Errorf("Expected two-digit years to be in the 2000s, found '%d'", c)
+	}
+	for _, raw := range []string{"1.2.3", "24", "20231315", "99999.1"} {
+		if CalVer.Valid(raw) {
+			t.Errorf("Expected '%s' to be invalid for calver", raw)
+		}
+	}
+}
+
+func TestFindScheme(t *testing.T) {
+	for _, name := range []string{"generic", "numeric", "calver", "semver", "PEP440", "debian", "rpm"} {
+		if FindScheme(name) == nil {
+			t.Errorf("Expected to find scheme '%s'", name)
+		}
+	}
+	if FindScheme("bogus") != nil {
+		t.Error("Expected no scheme 'bogus'")
+	}
+}
@@ -0,0 +1,79 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SemVerRegex matches a Semantic Versioning 2.0.0 version, with an optional leading "v"
+var SemVerRegex = regexp.MustCompile("^v?(0|[1-9]\\d*)\\.(0|[1-9]\\d*)\\.(0|[1-9]\\d*)" +
+	"(?:-((?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\\.(?:0|[1-9]\\d*|\\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?" +
+	"(?:\\+([0-9a-zA-Z-]+(?:\\.[0-9a-zA-Z-]+)*))?$")
+
+type semVer struct{}
+
+func (s semVer) String() string {
+	return "semver"
+}
+
+func (s semVer) Valid(raw string) bool {
+	return SemVerRegex.MatchString(strings.TrimSpace(raw))
+}
+
+// Compare orders by major, minor and patch, then by pre-release, ignoring build metadata
+func (s semVer) Compare(a, b string) int {
+	as := SemVerRegex.FindStringSubmatch(strings.TrimSpace(a))
+	bs := SemVerRegex.FindStringSubmatch(strings.TrimSpace(b))
+	if as == nil || bs == nil {
+		return Generic.Compare(a, b)
+	}
+	for i := 1; i < 4; i++ {
+		if c := compareNumeric(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	// A pre-release is older than its normal version
+	switch {
+	case as[4] == bs[4]:
+		return 0
+	case len(as[4]) == 0:
+		return 1
+	case len(bs[4]) == 0:
+		return -1
+	}
+	ap, bp := strings.Split(as[4], "."), strings.Split(bs[4], ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		an, bn := isDigits(ap[i]), isDigits(bp[i])
+		var c int
+		switch {
+		case an && bn:
+			c = compareNumeric(ap[i], bp[i])
+		case an:
+			c = -1
+		case bn:
+			c = 1
+		default:
+			c = strings.Compare(ap[i], bp[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return len(ap) - len(bp)
+}
@@ -0,0 +1,119 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Stability is how ready for use a release is, from the least stable to the most
+type Stability int
+
+const (
+	// Snapshot is an automated build, like a nightly
+	Snapshot Stability = iota
+	// Dev is a development release
+	Dev
+	// Alpha is an alpha release
+	Alpha
+	// Beta is a beta release, or an early access preview
+	Beta
+	// RC is a release candidate, or any other pre-release
+	RC
+	// Stable is a final release
+	Stable
+)
+
+// stabilityNames are the names of each kind of stability
+var stabilityNames = []string{"snapshot", "dev", "alpha", "beta", "rc", "stable"}
+
+// String gives the name of this stability
+func (s Stability) String() string {
+	if s < Snapshot || s > Stable {
+		return "unknown"
+	}
+	return stabilityNames[s]
+}
+
+// ParseStability gets a stability by name
+func ParseStability(name string) (Stability, error) {
+	for i, n := range stabilityNames {
+		if strings.EqualFold(n, name) {
+			return Stability(i), nil
+		}
+	}
+	return Stable, fmt.Errorf("unknown stability '%s', must be one of: %s", name, strings.Join(stabilityNames, ", "))
+}
+
+// stabilityWords are the words which mark a version as unstable
+var stabilityWords = map[string]Stability{
+	"snapshot":   Snapshot,
+	"nightly":    Snapshot,
+	"master":     Snapshot,
+	"dev":        Dev,
+	"devel":      Dev,
+	"unstable":   Dev,
+	"donotuse":   Dev,
+	"alpha":      Alpha,
+	"beta":       Beta,
+	"eap":        Beta,
+	"rc":         RC,
+	"pre":        RC,
+	"preview":    RC,
+	"prerelease": RC,
+}
+
+// stabilityLetters are the letters which mark a version as unstable, only when numbered like "1.0a1"
+var stabilityLetters = map[string]Stability{
+	"a": Alpha,
+	"b": Beta,
+	"c": RC,
+}
+
+// Classify finds the stability of a raw version from the words in it, like "1.0-beta2" or "1.0a1",
+// where a lone letter like "1.1.1w" is still considered stable
+func Classify(raw string) Stability {
+	// Split into runs of letters and digits
+	var runs []string
+	var run []rune
+	for _, char := range raw + "." {
+		if len(run) > 0 && (!isAlnum(char) || unicode.IsLetter(char) != unicode.IsLetter(run[0])) {
+			runs = append(runs, strings.ToLower(string(run)))
+			run = run[:0]
+		}
+		if isAlnum(char) {
+			run = append(run, char)
+		}
+	}
+	s := Stable
+	for i, run := range runs {
+		found, ok := stabilityWords[run]
+		if !ok && i > 0 && i < len(runs)-1 && isDigits(runs[i-1]) && isDigits(runs[i+1]) {
+			found, ok = stabilityLetters[run]
+		}
+		if ok && found < s {
+			s = found
+		}
+	}
+	return s
+}
+
+func isAlnum(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsDigit(char)
+}
@@ -0,0 +1,64 @@
+//
+// Copyright 2016-2021 Bryan T. Meyers <root@datadrake.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package version
+
+import (
+	"testing"
+)
+
+func classifyTest(t *testing.T, expected Stability, raws ...string) {
+	for _, raw := range raws {
+		if s := Classify(raw); s != expected {
+			t.Errorf("Expected '%s' to be %s, found %s", raw, expected, s)
+		}
+	}
+}
+
+func TestClassifyStable(t *testing.T) {
+	classifyTest(t, Stable, "1.2.3", "v2.0", "1.1.1w", "1.2.3a", "release-1.2", "1.0.post1")
+}
+
+func TestClassifyRC(t *testing.T) {
+	classifyTest(t, RC, "1.0.0-rc.1", "1.0rc1", "2.0-pre", "5.0-preview3", "1.0c1")
+}
+
+func TestClassifyBeta(t *testing.T) {
+	classifyTest(t, Beta, "1.0-beta", "1.0b2", "2023.1-EAP")
+}
+
+func TestClassifyAlpha(t *testing.T) {
+	classifyTest(t, Alpha, "1.0-alpha.1", "1.0a1", "44.alpha")
+}
+
+func TestClassifyDev(t *testing.T) {
+	classifyTest(t, Dev, "1.0.dev1", "1.0-rc1-dev", "3.27-unstable")
+}
+
+func TestClassifySnapshot(t *testing.T) {
+	classifyTest(t, Snapshot, "nightly-2023-05-01", "1.0-SNAPSHOT", "master")
+}
+
+func TestParseStability(t *testing.T) {
+	for s := Snapshot; s <= Stable; s++ {
+		if found, err := ParseStability(s.String()); err != nil || found != s {
+			t.Errorf("Expected to parse %s, found %s", s, found)
+		}
+	}
+	if _, err := ParseStability("bogus"); err == nil {
+		t.Error("Expected 'bogus' to be an unknown stability")
+	}
+}
@@ -19,7 +19,6 @@ package version
 import (
 	"strconv"
 	"strings"
-	"time"
 	"unicode"
 )
 
@@ -143,6 +142,10 @@ func (v Version) Compare(old Version) int {
 			return result
 		}
 	}
+	// Any remaining pieces make the old version newer
+	if len(old) > len(v) {
+		return 1
+	}
 	return result
 }
 
@@ -151,32 +154,6 @@ func (v Version) Less(other Version) bool {
 	return v.Compare(other) < 0
 }
 
-const dateLayout = "20060102"
-
-// FindDate tries to parse a version into a date if possible
-func (v Version) FindDate() time.Time {
-	t, err := time.Parse(dateLayout, v[0])
-	if err == nil {
-		return t
-	}
-	thisYear, _, _ := time.Now().Date()
-	year, err := strconv.Atoi(v[0])
-	if err != nil {
-		return time.Time{}
-	}
-	if year > thisYear || year < (thisYear-20) {
-		return time.Time{}
-	}
-	var month, day int
-	if len(v) > 1 {
-		month, _ = strconv.Atoi(v[1])
-	}
-	if len(v) > 2 {
-		day, _ = strconv.Atoi(v[2])
-	}
-	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
-}
-
 // String converts a version to a string for printing
 func (v Version) String() string {
 	return strings.Join(v, ".")
@@ -73,6 +73,30 @@ func TestNewVersion5(t *testing.T) {
 	newVersionTest(t, rawVersion5, version5)
 }
 
+const rawVersion6 = "1.2"
+
+var version6 = Version{"1", "2"}
+
+func TestNewVersion6(t *testing.T) {
+	newVersionTest(t, rawVersion6, version6)
+}
+
+const rawVersion7 = "1.2.0"
+
+var version7 = Version{"1", "2", "0"}
+
+func TestNewVersion7(t *testing.T) {
+	newVersionTest(t, rawVersion7, version7)
+}
+
+const rawVersion8 = "1.2.1"
+
+var version8 = Version{"1", "2", "1"}
+
+func TestNewVersion8(t *testing.T) {
+	newVersionTest(t, rawVersion8, version8)
+}
+
 func TestVersionCompareEqual1(t *testing.T) {
 	if c := version1.Compare(version1); c != 0 {
 		t.Errorf("Should be equal, found '%d'", c)
@@ -103,6 +127,24 @@ func TestVersionCompare2(t *testing.T) {
 	}
 }
 
+func TestVersionCompare3(t *testing.T) {
+	if version6.Compare(version7) <= 0 {
+		t.Error("Should have been less")
+	}
+	if version7.Compare(version6) >= 0 {
+		t.Error("Should have been greater")
+	}
+}
+
+func TestVersionCompare4(t *testing.T) {
+	if version6.Compare(version8) <= 0 {
+		t.Error("Should have been less")
+	}
+	if version8.Compare(version6) >= 0 {
+		t.Error("Should have been greater")
+	}
+}
+
 func TestVersionLess1(t *testing.T) {
 	if version5.Less(version5) {
 		t.Error("Should not be less: equal")
@@ -120,3 +162,21 @@ func TestVersionLess3(t *testing.T) {
 		t.Error("Should be less: less")
 	}
 }
+
+func TestVersionLess4(t *testing.T) {
+	if version6.Less(version7) {
+		t.Error("Should not be less: greater")
+	}
+	if !version7.Less(version6) {
+		t.Error("Should be less: less")
+	}
+}
+
+func TestVersionLess5(t *testing.T) {
+	if version6.Less(version8) {
+		t.Error("Should not be less: greater")
+	}
+	if !version8.Less(version6) {
+		t.Error("Should be less: less")
+	}
+}